@@ -0,0 +1,175 @@
+// Package convert holds the mapping of the Swagger 1.2 listing, declarations and models collected by the parser
+// which is shared by the Swagger 2.0 and OpenAPI 3 documents. The swagger2 and openapi3 packages only add their own shapes
+package convert
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/yvasiyarov/swagger/parser"
+)
+
+// Responses objects must contain at least one response code, operations without @Success or @Failure get this one
+const (
+	DefaultResponseCode        = "default"
+	DefaultResponseDescription = "Successful operation"
+)
+
+type Info struct {
+	Title          string   `json:"title"`
+	Description    string   `json:"description,omitempty"`
+	TermsOfService string   `json:"termsOfService,omitempty"`
+	Contact        *Contact `json:"contact,omitempty"`
+	License        *License `json:"license,omitempty"`
+	Version        string   `json:"version"`
+}
+
+type Contact struct {
+	Name  string `json:"name,omitempty"`
+	Url   string `json:"url,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+type License struct {
+	Name string `json:"name"`
+	Url  string `json:"url,omitempty"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of a path, O is the Operation of the spec
+type PathItem[O any] struct {
+	Get     *O `json:"get,omitempty"`
+	Put     *O `json:"put,omitempty"`
+	Post    *O `json:"post,omitempty"`
+	Delete  *O `json:"delete,omitempty"`
+	Options *O `json:"options,omitempty"`
+	Head    *O `json:"head,omitempty"`
+	Patch   *O `json:"patch,omitempty"`
+}
+
+func (pathItem *PathItem[O]) SetOperation(httpMethod string, op *O) {
+	switch strings.ToUpper(httpMethod) {
+	case "GET":
+		pathItem.Get = op
+	case "PUT":
+		pathItem.Put = op
+	case "POST":
+		pathItem.Post = op
+	case "DELETE":
+		pathItem.Delete = op
+	case "OPTIONS":
+		pathItem.Options = op
+	case "HEAD":
+		pathItem.Head = op
+	case "PATCH":
+		pathItem.Patch = op
+	}
+}
+
+func NewInfo(listing *parser.ResourceListing) Info {
+	info := Info{
+		Title:          listing.Infos.Title,
+		Description:    listing.Infos.Description,
+		TermsOfService: listing.Infos.TermsOfServiceUrl,
+		Version:        listing.ApiVersion,
+	}
+
+	if contact := listing.Infos.Contact; contact != "" {
+		info.Contact = &Contact{}
+		if strings.Contains(contact, "@") && !strings.Contains(contact, "://") {
+			info.Contact.Email = contact
+		} else if strings.Contains(contact, "://") {
+			info.Contact.Url = contact
+		} else {
+			info.Contact.Name = contact
+		}
+	}
+
+	if listing.Infos.License != "" {
+		info.License = &License{
+			Name: listing.Infos.License,
+			Url:  listing.Infos.LicenseUrl,
+		}
+	}
+	return info
+}
+
+// NewTags returns a tag for each resource of the listing
+func NewTags(listing *parser.ResourceListing) []*Tag {
+	var tags []*Tag
+	for _, apiRef := range listing.Apis {
+		tags = append(tags, &Tag{
+			Name:        strings.TrimPrefix(apiRef.Path, "/"),
+			Description: apiRef.Description,
+		})
+	}
+	return tags
+}
+
+// Resources returns the resources of the API declarations in alphabetical order
+func Resources(p *parser.Parser) []string {
+	resources := make([]string, 0, len(p.TopLevelApis))
+	for resource := range p.TopLevelApis {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	return resources
+}
+
+// Operations calls fn with the operations of all resources and their absolute paths
+func Operations(p *parser.Parser, fn func(resource, path string, op *parser.Operation)) {
+	for _, resource := range Resources(p) {
+		for _, api := range p.TopLevelApis[resource].Apis {
+			path := api.Path
+			if !strings.HasPrefix(path, "/") {
+				path = "/" + path
+			}
+			for _, op := range api.Operations {
+				fn(resource, path, op)
+			}
+		}
+	}
+}
+
+// Description returns the notes of the operation. Single line descriptions are the summary already
+func Description(op *parser.Operation) string {
+	if op.Notes == op.Summary {
+		return ""
+	}
+	return op.Notes
+}
+
+// ResponseDescription returns the message of the response, or the status text of its code
+func ResponseDescription(msg parser.ResponseMessage) string {
+	if msg.Message == "" {
+		return http.StatusText(msg.Code)
+	}
+	return msg.Message
+}
+
+// SecurityRequirements lists the security schemes of the operation as alternatives, in alphabetical order
+func SecurityRequirements(authorizations map[string][]parser.Scope) []map[string][]string {
+	names := make([]string, 0, len(authorizations))
+	for name := range authorizations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	requirements := make([]map[string][]string, 0, len(names))
+	for _, name := range names {
+		scopes := make([]string, 0, len(authorizations[name]))
+		for _, scope := range authorizations[name] {
+			scopes = append(scopes, scope.Scope)
+		}
+		requirements = append(requirements, map[string][]string{name: scopes})
+	}
+	if len(requirements) == 0 {
+		return nil
+	}
+	return requirements
+}
@@ -0,0 +1,227 @@
+package convert
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/yvasiyarov/swagger/parser"
+)
+
+// Schema is the subset of JSON Schema understood by both Swagger 2.0 and OpenAPI 3
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+
+	AdditionalProperties *Schema `json:"additionalProperties,omitempty"`
+
+	Enum             []interface{} `json:"enum,omitempty"`
+	Minimum          *float64      `json:"minimum,omitempty"`
+	Maximum          *float64      `json:"maximum,omitempty"`
+	ExclusiveMinimum bool          `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum bool          `json:"exclusiveMaximum,omitempty"`
+	MinLength        *int64        `json:"minLength,omitempty"`
+	MaxLength        *int64        `json:"maxLength,omitempty"`
+	MinItems         *int64        `json:"minItems,omitempty"`
+	MaxItems         *int64        `json:"maxItems,omitempty"`
+	Pattern          string        `json:"pattern,omitempty"`
+
+	Default     interface{} `json:"default,omitempty"`
+	Example     interface{} `json:"example,omitempty"`
+	Deprecated  bool        `json:"deprecated,omitempty"`
+	XDeprecated bool        `json:"x-deprecated,omitempty"` // Swagger 2.0 has no deprecated schemas
+}
+
+// Schemas converts the models and types of the parser to schemas
+type Schemas struct {
+	// Prefix of the $ref to a model, like "#/definitions/"
+	RefPrefix string
+	// Schema of file uploads
+	File Schema
+	// Deprecated schemas are marked with x-deprecated
+	DeprecatedExtension bool
+	// Schemas of the models, by model id
+	Models map[string]*Schema
+}
+
+// AddModels converts the models of all resources. The model ids are collected first,
+// so we know which type names should become a $ref
+func (schemas *Schemas) AddModels(p *parser.Parser) {
+	if schemas.Models == nil {
+		schemas.Models = make(map[string]*Schema)
+	}
+	resources := Resources(p)
+	for _, resource := range resources {
+		for modelId := range p.TopLevelApis[resource].Models {
+			schemas.Models[modelId] = &Schema{Type: "object"}
+		}
+	}
+	for _, resource := range resources {
+		for modelId, model := range p.TopLevelApis[resource].Models {
+			schemas.Models[modelId] = schemas.ModelSchema(model)
+		}
+	}
+}
+
+func (schemas *Schemas) ModelSchema(model *parser.Model) *Schema {
+	schema := &Schema{
+		Type:     "object",
+		Required: model.Required,
+	}
+	schemas.setDeprecated(schema, model.Deprecated)
+
+	if len(model.Properties) > 0 {
+		schema.Properties = make(map[string]*Schema, len(model.Properties))
+	}
+	for name, property := range model.Properties {
+		schema.Properties[name] = schemas.PropertySchema(property)
+	}
+
+	return schema
+}
+
+// PropertySchema converts a model property. Maps become objects with additionalProperties of the value type
+func (schemas *Schemas) PropertySchema(property *parser.ModelProperty) *Schema {
+	var schema *Schema
+	if property.AdditionalProperties != nil {
+		schema = &Schema{
+			Type:                 "object",
+			AdditionalProperties: schemas.PropertySchema(property.AdditionalProperties),
+		}
+	} else if property.Type == "array" {
+		itemType := property.Items.Type
+		if itemType == "" {
+			itemType = property.Items.Ref
+		}
+		schema = &Schema{
+			Type:  "array",
+			Items: schemas.SchemaForType(itemType),
+		}
+		if schema.Items.Ref == "" && property.Items.Format != "" {
+			schema.Items.Format = property.Items.Format
+		}
+		for _, value := range property.Items.Enum {
+			schema.Items.Enum = append(schema.Items.Enum, EnumValue(schema.Items.Type, value))
+		}
+	} else {
+		schema = schemas.SchemaForType(property.Type)
+		if schema.Ref == "" && property.Format != "" {
+			schema.Format = property.Format
+		}
+	}
+
+	// Siblings of $ref are ignored, so the description and constraints are only kept on inline schemas
+	if schema.Ref == "" {
+		schema.Description = property.Description
+		setConstraints(schema, property)
+		if property.Example != "" {
+			schema.Example = ExampleValue(schema, property.Example)
+		}
+		schemas.setDeprecated(schema, property.Deprecated)
+	}
+	return schema
+}
+
+// SchemaForType returns a $ref to a known model or the schema of a primitive type
+func (schemas *Schemas) SchemaForType(typeName string) *Schema {
+	if _, ok := schemas.Models[typeName]; ok {
+		return &Schema{Ref: schemas.RefPrefix + typeName}
+	}
+
+	if strings.Contains(typeName, "interface") {
+		// Any value
+		return &Schema{}
+	}
+	swaggerType, format := parser.SwaggerType(typeName)
+	if swaggerType == "file" {
+		file := schemas.File
+		return &file
+	}
+	if swaggerType == "" {
+		return &Schema{Type: "object"}
+	}
+	return &Schema{Type: swaggerType, Format: format}
+}
+
+func (schemas *Schemas) setDeprecated(schema *Schema, deprecated bool) {
+	if schemas.DeprecatedExtension {
+		schema.XDeprecated = deprecated
+	} else {
+		schema.Deprecated = deprecated
+	}
+}
+
+// setConstraints copies the validation constraints of the property to its schema
+func setConstraints(schema *Schema, property *parser.ModelProperty) {
+	schema.Minimum, schema.Maximum = property.Minimum, property.Maximum
+	schema.ExclusiveMinimum, schema.ExclusiveMaximum = property.ExclusiveMinimum, property.ExclusiveMaximum
+	schema.MinLength, schema.MaxLength = property.MinLength, property.MaxLength
+	schema.MinItems, schema.MaxItems = property.MinItems, property.MaxItems
+	schema.Pattern = property.Pattern
+
+	for _, value := range property.Enum {
+		schema.Enum = append(schema.Enum, EnumValue(schema.Type, value))
+	}
+}
+
+// EnumValue converts an enum value to the JSON type of the schema
+func EnumValue(schemaType, value string) interface{} {
+	switch schemaType {
+	case "integer":
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return v
+		}
+	case "number":
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			return v
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v
+		}
+	}
+	return value
+}
+
+// ExampleValue converts an example or default value to the JSON type of the schema.
+// Examples of arrays are comma separated items, examples of objects are JSON
+func ExampleValue(schema *Schema, value string) interface{} {
+	var decoded interface{}
+	switch schema.Type {
+	case "string":
+		return value
+	case "integer", "number", "boolean":
+		return EnumValue(schema.Type, value)
+	case "array":
+		if json.Unmarshal([]byte(value), &decoded) == nil {
+			return decoded
+		}
+		itemType := ""
+		if schema.Items != nil {
+			itemType = schema.Items.Type
+		}
+		items := make([]interface{}, 0)
+		for _, item := range strings.Split(value, ",") {
+			items = append(items, EnumValue(itemType, strings.TrimSpace(item)))
+		}
+		return items
+	}
+	if json.Unmarshal([]byte(value), &decoded) == nil {
+		return decoded
+	}
+	return value
+}
+
+// ResponseExample decodes JSON examples, examples of other content types are kept as text
+func ResponseExample(contentType, example string) interface{} {
+	var decoded interface{}
+	if strings.Contains(contentType, "json") && json.Unmarshal([]byte(example), &decoded) == nil {
+		return decoded
+	}
+	return example
+}
@@ -0,0 +1,68 @@
+package convert_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/yvasiyarov/swagger/convert"
+	"github.com/yvasiyarov/swagger/parser"
+)
+
+type SchemaSuite struct {
+	suite.Suite
+	schemas *convert.Schemas
+}
+
+func (suite *SchemaSuite) SetupTest() {
+	suite.schemas = &convert.Schemas{
+		RefPrefix: "#/definitions/",
+		File:      convert.Schema{Type: "file"},
+		Models:    map[string]*convert.Schema{"test.Order": {Type: "object"}},
+	}
+}
+
+func (suite *SchemaSuite) TestSchemaForType() {
+	assert.Equal(suite.T(), &convert.Schema{Ref: "#/definitions/test.Order"}, suite.schemas.SchemaForType("test.Order"), "Model not referenced")
+	assert.Equal(suite.T(), &convert.Schema{Type: "integer", Format: "int64"}, suite.schemas.SchemaForType("int64"), "Primitive not converted")
+	assert.Equal(suite.T(), &convert.Schema{}, suite.schemas.SchemaForType("interface{}"), "Any value not converted")
+	assert.Equal(suite.T(), &convert.Schema{Type: "object"}, suite.schemas.SchemaForType("test.Unknown"), "Unknown type not converted")
+
+	file := suite.schemas.SchemaForType("file")
+	assert.Equal(suite.T(), &convert.Schema{Type: "file"}, file, "File not converted")
+	file.Description = "Avatar"
+	assert.Empty(suite.T(), suite.schemas.File.Description, "File schema must be copied")
+}
+
+func (suite *SchemaSuite) TestDeprecated() {
+	model := &parser.Model{Deprecated: true, Properties: map[string]*parser.ModelProperty{
+		"id":    {Type: "int64", Deprecated: true},
+		"order": {Type: "test.Order", Deprecated: true},
+	}}
+
+	schema := suite.schemas.ModelSchema(model)
+	assert.True(suite.T(), schema.Deprecated, "Deprecated model not converted")
+	assert.False(suite.T(), schema.Properties["order"].Deprecated, "Siblings of $ref must not be set")
+
+	suite.schemas.DeprecatedExtension = true
+	schema = suite.schemas.ModelSchema(model)
+	assert.False(suite.T(), schema.Deprecated, "Deprecated must be an extension")
+	assert.True(suite.T(), schema.XDeprecated, "Deprecated model not converted")
+	assert.True(suite.T(), schema.Properties["id"].XDeprecated, "Deprecated property not converted")
+}
+
+func (suite *SchemaSuite) TestExampleValue() {
+	assert.Equal(suite.T(), "10", convert.ExampleValue(&convert.Schema{Type: "string"}, "10"), "String example not kept")
+	assert.Equal(suite.T(), int64(10), convert.ExampleValue(&convert.Schema{Type: "integer"}, "10"), "Integer example not converted")
+	assert.Equal(suite.T(), "ten", convert.ExampleValue(&convert.Schema{Type: "integer"}, "ten"), "Invalid example must be kept")
+	assert.Equal(suite.T(), []interface{}{int64(1), int64(2)}, convert.ExampleValue(&convert.Schema{Type: "array", Items: &convert.Schema{Type: "integer"}}, "1, 2"), "Array example not split")
+	assert.Equal(suite.T(), []interface{}{"a"}, convert.ExampleValue(&convert.Schema{Type: "array"}, `["a"]`), "JSON array example not decoded")
+	assert.Equal(suite.T(), map[string]interface{}{"id": float64(1)}, convert.ExampleValue(&convert.Schema{Ref: "#/definitions/test.Order"}, `{"id": 1}`), "Object example not decoded")
+
+	assert.Equal(suite.T(), map[string]interface{}{"id": float64(1)}, convert.ResponseExample(parser.ContentTypeJson, `{"id": 1}`), "JSON response example not decoded")
+	assert.Equal(suite.T(), `{"id": 1}`, convert.ResponseExample(parser.ContentTypePlain, `{"id": 1}`), "Text response example must be kept")
+}
+
+func TestSchemaSuite(t *testing.T) {
+	suite.Run(t, &SchemaSuite{})
+}
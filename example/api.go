@@ -27,7 +27,7 @@ func (c *Context) WriteResponse(response interface{}) {
 // @Failure 404 {object} APIError "Can not find ID"
 // @Router /testapi/get-string-by-int/{some_id} [get]
 func (c *Context) GetStringByInt(rw web.ResponseWriter, req *web.Request) {
-	c.WriteResponse(fmt.Sprint("Some data for %s ID", req.PathParams["some_id"]))
+	c.WriteResponse(fmt.Sprintf("Some data for %s ID", req.PathParams["some_id"]))
 }
 
 // @Title GetStructByInt
@@ -40,6 +40,8 @@ func (c *Context) GetStringByInt(rw web.ResponseWriter, req *web.Request) {
 // @Success 200 {object} StructureWithEmbededStructure
 // @Failure 400 {object} APIError "We need ID!!"
 // @Failure 404 {object} APIError "Can not find ID"
+// @Security OAuth2 read,write
+// @Security ApiKeyAuth
 // @Router /testapi/get-struct-by-int/{some_id} [get]
 func (c *Context) GetStructByInt(rw web.ResponseWriter, req *web.Request) {
 	c.WriteResponse(StructureWithEmbededStructure{})
@@ -81,8 +83,9 @@ func (c *Context) GetSimpleArrayByString(rw web.ResponseWriter, req *web.Request
 // @Produce  json
 // @Param   some_id     path    string     true        "Some ID"
 // @Param   offset     query    int     true        "Offset"
-// @Param   limit      query    int     true        "Offset"
+// @Param   limit      query    int     false       "Limit" default(10) example(20)
 // @Success 200 {array} SimpleStructureWithAnnotations
+// @Example 200 application/json examples/struct_array.json
 // @Failure 400 {object} APIError "We need ID!!"
 // @Failure 404 {object} APIError "Can not find ID"
 // @Router /testapi/get-struct-array-by-string/{some_id} [get]
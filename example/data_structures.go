@@ -1,7 +1,9 @@
 package example
 
 import (
-//	"github.com/yvasiyarov/swagger/example/subpackage"
+	"database/sql"
+	"fmt"
+	//	"github.com/yvasiyarov/swagger/example/subpackage"
 )
 
 type InterfaceType interface{}
@@ -14,7 +16,7 @@ type SimpleStructure struct {
 }
 
 type SimpleStructureWithAnnotations struct {
-	Id   int    `json:"id"`
+	Id   int    `json:"id" example:"42"`
 	Name string `json:"required,omitempty"`
 }
 
@@ -34,3 +36,35 @@ type APIError struct {
 	ErrorCode    int
 	ErrorMessage string
 }
+
+type StructureWithValidation struct {
+	Name  string   `json:"name" validate:"required,min=1,max=64,alphanum"`
+	Email string   `json:"email" binding:"required" validate:"email"`
+	Age   int      `json:"age" validate:"gte=18,lt=130"`
+	Role  string   `json:"role" validate:"oneof=admin 'power user' guest"`
+	Tags  []string `json:"tags" validate:"min=1,dive,max=10"`
+	Note  string   `json:"note" validate:"omitempty,len=8|len=16"`
+}
+
+// @SwaggerType string uuid
+type UUID [16]byte
+
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])), nil
+}
+
+type Money struct {
+	Units int64
+	Nanos int32
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`"%d.%09d"`, m.Units, m.Nanos)), nil
+}
+
+type StructureWithMarshalers struct {
+	Id      UUID
+	Price   *Money
+	Related []UUID
+	Deleted sql.NullBool
+}
@@ -2,10 +2,20 @@
 // @APITitle Swagger Example API
 // @APIDescription Swagger Example API
 // @BasePath http://127.0.0.1:3000/
+// @Environment dev http://127.0.0.1:3000/
+// @Environment prod https://api.yvasiyarov.com/
 // @Contact varyous@gmail.com
 // @TermsOfServiceUrl http://yvasiyarov.com/
 // @License BSD
 // @LicenseUrl http://yvasiyarov.com/
+// @SecurityDefinitions.apikey ApiKeyAuth
+// @In header
+// @Name Authorization
+// @SecurityDefinitions.oauth2.accessCode OAuth2
+// @AuthorizationUrl http://yvasiyarov.com/oauth/authorize
+// @TokenUrl http://yvasiyarov.com/oauth/token
+// @Scope.read Grants read access
+// @Scope.write Grants write access
 package main
 
 import (
@@ -0,0 +1,159 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	diffContext = 3
+	// Above this size of the changed region, it is shown as fully replaced instead of computing the LCS
+	maxDiffCells = 16 << 20
+)
+
+type diffLine struct {
+	kind byte // ' ', '-' or '+'
+	text string
+}
+
+// unifiedDiff returns the changes from oldText to newText in unified format, empty if they are equal
+func unifiedDiff(fromFile, toFile, oldText, newText string) string {
+	lines := diffLines(splitLines(oldText), splitLines(newText))
+
+	var out strings.Builder
+	for start := 0; start < len(lines); {
+		for start < len(lines) && lines[start].kind == ' ' {
+			start++
+		}
+		if start == len(lines) {
+			break
+		}
+
+		// Changes closer than twice the context share a hunk
+		last := start
+		for next := start + 1; next < len(lines); next++ {
+			if lines[next].kind != ' ' {
+				if next-last > 2*diffContext {
+					break
+				}
+				last = next
+			}
+		}
+		hunkStart, hunkEnd := start-diffContext, last+diffContext+1
+		if hunkStart < 0 {
+			hunkStart = 0
+		}
+		if hunkEnd > len(lines) {
+			hunkEnd = len(lines)
+		}
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromFile, toFile)
+		}
+		oldStart, oldCount := lineRange(lines, hunkStart, hunkEnd, '+')
+		newStart, newCount := lineRange(lines, hunkStart, hunkEnd, '-')
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		for _, line := range lines[hunkStart:hunkEnd] {
+			out.WriteByte(line.kind)
+			out.WriteString(line.text)
+			if !strings.HasSuffix(line.text, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		start = hunkEnd
+	}
+	return out.String()
+}
+
+// lineRange returns the first line number and the number of lines of a hunk in one of the texts,
+// the lines of the other text are of kind skip
+func lineRange(lines []diffLine, start, end int, skip byte) (int, int) {
+	first, count := 1, 0
+	for i, line := range lines[:end] {
+		if line.kind == skip {
+			continue
+		}
+		if i < start {
+			first++
+		} else {
+			count++
+		}
+	}
+	if count == 0 {
+		// Empty ranges start at the line before
+		first--
+	}
+	return first, count
+}
+
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes the longest common subsequence of the lines, after the common prefix and suffix are skipped
+func diffLines(a, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]diffLine, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		lines = append(lines, diffLine{' ', line})
+	}
+
+	commonSuffix := a[len(a)-suffix:]
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	i, j := 0, 0
+	if len(a)*len(b) <= maxDiffCells {
+		// lcs[i][j] is the length of the LCS of a[i:] and b[j:]
+		lcs := make([][]int32, len(a)+1)
+		for i := range lcs {
+			lcs[i] = make([]int32, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				if a[i] == b[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		for i < len(a) && j < len(b) {
+			switch {
+			case a[i] == b[j]:
+				lines = append(lines, diffLine{' ', a[i]})
+				i++
+				j++
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lines = append(lines, diffLine{'-', a[i]})
+				i++
+			default:
+				lines = append(lines, diffLine{'+', b[j]})
+				j++
+			}
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, diffLine{'+', b[j]})
+	}
+
+	for _, line := range commonSuffix {
+		lines = append(lines, diffLine{' ', line})
+	}
+	return lines
+}
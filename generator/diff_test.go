@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	assert.Empty(t, unifiedDiff("a", "b", "same\n", "same\n"), "Equal texts have no diff")
+
+	oldText := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n"
+	newText := strings.Replace(strings.Replace(oldText, "2\n", "two\n", 1), "15\n", "", 1)
+	assert.Equal(t, `--- docs.go
++++ docs.go (generated)
+@@ -1,5 +1,5 @@
+ 1
+-2
++two
+ 3
+ 4
+ 5
+@@ -12,5 +12,4 @@
+ 12
+ 13
+ 14
+-15
+ 16
+`, unifiedDiff("docs.go", "docs.go (generated)", oldText, newText), "Distant changes must be in separate hunks")
+
+	assert.Equal(t, `--- /dev/null
++++ new.json
+@@ -0,0 +1,1 @@
++{}
+\ No newline at end of file
+`, unifiedDiff("/dev/null", "new.json", "", "{}"), "New file not diffed")
+}
+
+func TestCheckOutput(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swagger-check")
+	assert.NoError(t, err, "Can not create temp dir")
+	defer os.RemoveAll(dir)
+
+	var diff bytes.Buffer
+	out := &output{diff: &diff}
+	filename := filepath.Join(dir, "user", "index.json")
+	assert.NoError(t, out.writeFile(filename, []byte("{\n    \"a\": 1\n}")), "Can not write file")
+
+	out.check = true
+	assert.NoError(t, out.writeFile(filename, []byte("{\n    \"a\": 1\n}")), "Can not check file")
+	assert.Empty(t, out.stale, "Unchanged file reported as stale")
+	assert.NoError(t, out.writeFile(filename, []byte("{\n    \"a\": 2\n}")), "Can not check file")
+	assert.NoError(t, out.writeFile(filepath.Join(dir, "missing.json"), []byte("{}")), "Can not check missing file")
+	assert.Equal(t, []string{filename, filepath.Join(dir, "missing.json")}, out.stale, "Changed files not reported")
+	assert.Contains(t, diff.String(), "-    \"a\": 1\n+    \"a\": 2\n", "Diff not printed")
+
+	current, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err, "Can not read file")
+	assert.Equal(t, "{\n    \"a\": 1\n}", string(current), "Check mode must not write files")
+	_, err = os.Stat(filepath.Join(dir, "missing.json"))
+	assert.True(t, os.IsNotExist(err), "Check mode must not create files")
+}
+
+func TestCheckExtraFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swagger-check")
+	assert.NoError(t, err, "Can not create temp dir")
+	defer os.RemoveAll(dir)
+
+	out := &output{}
+	for _, resource := range []string{"user", "order"} {
+		assert.NoError(t, out.writeFile(filepath.Join(dir, resource, "index.json"), []byte("{}")), "Can not write file")
+	}
+	assert.NoError(t, out.writeFile(filepath.Join(dir, "order", "notes.txt"), []byte("notes")), "Can not write file")
+
+	var diff bytes.Buffer
+	out = &output{check: true, diff: &diff}
+	assert.NoError(t, out.writeFile(filepath.Join(dir, "user", "index.json"), []byte("{}")), "Can not check file")
+	assert.NoError(t, out.checkExtraFiles(dir, "index.json"), "Can not check extra files")
+	assert.Equal(t, []string{filepath.Join(dir, "order", "index.json")}, out.stale, "Spec of removed resource not reported")
+	assert.Contains(t, diff.String(), "+++ /dev/null\n@@ -1,1 +0,0 @@\n-{}\n", "Removal not diffed")
+
+	_, err = os.Stat(filepath.Join(dir, "order", "index.json"))
+	assert.NoError(t, err, "Check mode must not remove files")
+	assert.NoError(t, (&output{check: true}).checkExtraFiles(filepath.Join(dir, "missing"), "index.json"), "Missing output dir must not fail")
+}
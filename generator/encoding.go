@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	AVAILABLE_ENCODINGS = "json|yaml"
+)
+
+// marshalSpec serialises a spec as indented JSON or as YAML.
+// YAML keys keep the order of the JSON fields, so the output is stable and follows structs.go
+func marshalSpec(spec interface{}, encoding string) ([]byte, error) {
+	data, err := json.MarshalIndent(spec, "", "    ")
+	if err != nil || encoding != "yaml" {
+		return data, err
+	}
+	return jsonToYaml(data)
+}
+
+// specFileName replaces the .json extension of fileName for YAML specs
+func specFileName(fileName, encoding string) string {
+	if encoding == "yaml" {
+		return strings.TrimSuffix(fileName, ".json") + ".yaml"
+	}
+	return fileName
+}
+
+func jsonToYaml(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	node, err := yamlNode(decoder)
+	if err != nil {
+		return nil, fmt.Errorf("Can not convert JSON to YAML: %v", err)
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return nil, fmt.Errorf("Can not serialise spec to YAML: %v", err)
+	}
+	encoder.Close()
+	return out.Bytes(), nil
+}
+
+// yamlNode reads the next JSON value from decoder, objects become mappings in the order of their keys
+func yamlNode(decoder *json.Decoder) (*yaml.Node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := token.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if value == '{' {
+			node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		for decoder.More() {
+			if node.Kind == yaml.MappingNode {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)})
+			}
+			child, err := yamlNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		// Closing delimiter
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		if len(node.Content) == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		return node, nil
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	case json.Number:
+		if strings.ContainsAny(value.String(), ".eE") {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: value.String()}, nil
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: value.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(value)}, nil
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+	return nil, fmt.Errorf("Unexpected JSON token %v", token)
+}
@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yvasiyarov/swagger/parser"
+)
+
+func TestMarshalSpecYaml(t *testing.T) {
+	listing := &parser.ResourceListing{
+		ApiVersion:     "1.0.0",
+		SwaggerVersion: parser.SwaggerVersion,
+		BasePath:       "{{.}}",
+		Apis:           []*parser.ApiRef{{Path: "/user", Description: "Users: list, create"}},
+		Environments:   map[string]string{"prod": "https://api.example.com", "dev": "http://127.0.0.1:3000"},
+	}
+
+	spec, err := marshalSpec(listing, "yaml")
+	assert.NoError(t, err, "Can not serialise spec to YAML")
+	assert.Equal(t, `apiVersion: 1.0.0
+swaggerVersion: "1.2"
+basePath: '{{.}}'
+apis:
+  - path: /user
+    description: 'Users: list, create'
+info: {}
+environments:
+  dev: http://127.0.0.1:3000
+  prod: https://api.example.com
+`, string(spec), "YAML keys must follow the JSON field order")
+
+	spec, err = marshalSpec(map[string]interface{}{"count": 2, "ratio": 0.5, "enabled": true, "items": []string{}, "none": nil}, "yaml")
+	assert.NoError(t, err, "Can not serialise spec to YAML")
+	assert.Equal(t, "count: 2\nenabled: true\nitems: []\nnone: null\nratio: 0.5\n", string(spec), "JSON scalars not converted")
+
+	spec, err = marshalSpec(listing, "json")
+	assert.NoError(t, err, "Can not serialise spec to JSON")
+	assert.Contains(t, string(spec), `    "apiVersion": "1.0.0",`, "JSON must stay the default encoding")
+}
+
+func TestSpecFileName(t *testing.T) {
+	assert.Equal(t, "openapi.yaml", specFileName("openapi.json", "yaml"), "Extension not replaced")
+	assert.Equal(t, "openapi.json", specFileName("openapi.json", "json"), "JSON file name must be kept")
+}
+
+func TestYamlGoVariables(t *testing.T) {
+	for _, format := range []string{"go", "gopkg"} {
+		err := Run(Params{OutputFormat: format, Encoding: "yaml"})
+		assert.EqualError(t, err, "-encoding yaml is not supported by -format "+format+", its Go variables must hold JSON", "YAML must be rejected for Go variables")
+	}
+}
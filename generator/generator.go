@@ -2,20 +2,24 @@ package generator
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/sirupsen/logrus"
+	"github.com/yvasiyarov/swagger/convert"
 	"github.com/yvasiyarov/swagger/markup"
+	"github.com/yvasiyarov/swagger/openapi3"
 	"github.com/yvasiyarov/swagger/parser"
+	"github.com/yvasiyarov/swagger/swagger2"
 )
 
 const (
-	AVAILABLE_FORMATS = "go|gopkg|swagger|asciidoc|markdown|confluence"
+	AVAILABLE_FORMATS = "go|gopkg|swagger|swagger2|openapi3|asciidoc|markdown|confluence|lint"
 )
 
 var (
@@ -38,85 +42,141 @@ var ApiDescriptionsJson = {{apiDescriptions}}
 `
 )
 
-func generateSwaggerDocs(parser *parser.Parser, outputSpec string, pkg bool) error {
-	fd, err := os.Create(path.Join(outputSpec, "docs.go"))
-	if err != nil {
-		return fmt.Errorf("Can not create document file: %v\n", err)
-	}
-	defer fd.Close()
-
+func generateSwaggerDocs(parser *parser.Parser, outputSpec string, pkg bool, out *output) error {
 	var apiDescriptions bytes.Buffer
-	for apiKey, apiDescription := range parser.TopLevelApis {
+	for _, apiKey := range convert.Resources(parser) {
+		apiDescription := parser.TopLevelApis[apiKey]
 		apiDescriptions.WriteString("\"" + apiKey + "\":")
 
 		apiDescriptions.WriteString("`")
-		json, err := json.MarshalIndent(apiDescription, "", "    ")
+		spec, err := marshalSpec(apiDescription, "json")
 		if err != nil {
-			return fmt.Errorf("Can not serialise []ApiDescription to JSON: %v\n", err)
+			return fmt.Errorf("Can not serialise []ApiDescription: %v\n", err)
 		}
-		apiDescriptions.Write(json)
+		apiDescriptions.Write(spec)
 		apiDescriptions.WriteString("`,")
 	}
 
+	resourceListing, err := marshalSpec(parser.Listing, "json")
+	if err != nil {
+		return fmt.Errorf("Can not serialise ResourceListing: %v\n", err)
+	}
+
 	var doc string
 	if pkg {
-		doc = strings.Replace(generatedPkgTemplate, "{{resourceListing}}", "`"+string(parser.GetResourceListingJson())+"`", -1)
+		doc = strings.Replace(generatedPkgTemplate, "{{resourceListing}}", "`"+string(resourceListing)+"`", -1)
 		doc = strings.Replace(doc, "{{apiDescriptions}}", "map[string]string{"+apiDescriptions.String()+"}", -1)
 		packageName := strings.Split(outputSpec, "/")
 		doc = strings.Replace(doc, "{{packageName}}", packageName[len(packageName)-1], -1)
 	} else {
-		doc = strings.Replace(generatedFileTemplate, "{{resourceListing}}", "`"+string(parser.GetResourceListingJson())+"`", -1)
+		doc = strings.Replace(generatedFileTemplate, "{{resourceListing}}", "`"+string(resourceListing)+"`", -1)
 		doc = strings.Replace(doc, "{{apiDescriptions}}", "map[string]string{"+apiDescriptions.String()+"}", -1)
 	}
 
-	fd.WriteString(doc)
-
-	return nil
+	return out.writeFile(path.Join(outputSpec, "docs.go"), []byte(doc))
 }
 
-func generateSwaggerUiFiles(parser *parser.Parser, outputSpec string) error {
-	fd, err := os.Create(path.Join(outputSpec, "index.json"))
+func generateSwaggerUiFiles(parser *parser.Parser, outputSpec string, encoding string, out *output) error {
+	resourceListing, err := marshalSpec(parser.Listing, encoding)
 	if err != nil {
-		return fmt.Errorf("Can not create the master index.json file: %v\n", err)
+		return fmt.Errorf("Can not serialise ResourceListing: %v\n", err)
+	}
+
+	indexFile := specFileName("index.json", encoding)
+	if err := out.writeFile(path.Join(outputSpec, indexFile), resourceListing); err != nil {
+		return err
 	}
-	defer fd.Close()
-	fd.WriteString(string(parser.GetResourceListingJson()))
 
-	for apiKey, apiDescription := range parser.TopLevelApis {
-		err = os.MkdirAll(path.Join(outputSpec, apiKey), 0777)
+	for _, apiKey := range convert.Resources(parser) {
+		spec, err := marshalSpec(parser.TopLevelApis[apiKey], encoding)
 		if err != nil {
+			return fmt.Errorf("Can not serialise []ApiDescription: %v\n", err)
+		}
+		if err := out.writeFile(path.Join(outputSpec, apiKey, indexFile), spec); err != nil {
 			return err
 		}
+	}
 
-		fd, err = os.Create(path.Join(outputSpec, apiKey, "index.json"))
-		if err != nil {
-			return fmt.Errorf("Can not create the %s/index.json file: %v\n", apiKey, err)
-		}
-		defer fd.Close()
+	// Specs of removed resources are left on disk
+	return out.checkExtraFiles(outputSpec, indexFile)
+}
 
-		json, err := json.MarshalIndent(apiDescription, "", "    ")
-		if err != nil {
-			return fmt.Errorf("Can not serialise []ApiDescription to JSON: %v\n", err)
+// generateSpecFile writes a single document spec (Swagger 2.0, OpenAPI 3) to outputSpec.
+// If outputSpec is empty or a directory, defaultFileName is used
+func generateSpecFile(spec interface{}, outputSpec, defaultFileName, encoding string, out *output) error {
+	defaultFileName = specFileName(defaultFileName, encoding)
+	filename := outputSpec
+	if filename == "" {
+		filename = defaultFileName
+	} else if info, err := os.Stat(filename); err == nil && info.IsDir() {
+		filename = path.Join(filename, defaultFileName)
+	}
+
+	data, err := marshalSpec(spec, encoding)
+	if err != nil {
+		return fmt.Errorf("Can not serialise spec: %v\n", err)
+	}
+
+	return out.writeFile(filename, data)
+}
+
+// lintApi prints the parse errors and the skipped or inconsistent annotations as file:line: message.
+// It fails if there are any, so CI jobs can stop broken docs
+func lintApi(parser *parser.Parser, out io.Writer) error {
+	// Full slice expression, so the warnings are appended to a copy of the errors
+	issues := append(parser.Errors[:len(parser.Errors):len(parser.Errors)], parser.Warnings...)
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
 		}
+		return issues[i].Line < issues[j].Line
+	})
 
-		fd.Write(json)
-		log.Printf("Wrote %v/index.json", apiKey)
+	for _, issue := range issues {
+		if issue.File != "" {
+			fmt.Fprintf(out, "%s:%d: %v\n", issue.File, issue.Line, issue.Err)
+		} else {
+			fmt.Fprintf(out, "package %s: %v\n", issue.Package, issue.Err)
+		}
 	}
 
+	if len(issues) > 0 {
+		return fmt.Errorf("Found %d annotation problems", len(issues))
+	}
 	return nil
 }
 
 type Params struct {
-	ApiPackage, MainApiFile, OutputFormat, OutputSpec, ControllerClass, Ignore, VendoringPath string
-	ContentsTable, Models, DisableVendoring                                                   bool
+	ApiPackage, MainApiFile, OutputFormat, OutputSpec, ControllerClass, Ignore, VendoringPath, Encoding string
+	ContentsTable, Models, DisableVendoring, CollectErrors, DiscoverRoutes, SortOutput, Check           bool
 }
 
 func Run(params Params) error {
+	format := strings.ToLower(params.OutputFormat)
+	encoding := strings.ToLower(params.Encoding)
+	if encoding == "" {
+		encoding = "json"
+	} else if encoding != "json" && encoding != "yaml" {
+		return fmt.Errorf("Invalid -encoding specified. Must be one of %v.", AVAILABLE_ENCODINGS)
+	}
+	if encoding == "yaml" && (format == "go" || format == "gopkg") {
+		// The Go variables are served to Swagger UI as they are, which expects JSON
+		return fmt.Errorf("-encoding yaml is not supported by -format %s, its Go variables must hold JSON", format)
+	}
+
 	parser, err := parser.NewParser(params.ApiPackage, params.ControllerClass, params.Ignore,
 		params.VendoringPath, params.DisableVendoring)
 	if err != nil {
 		return fmt.Errorf("Unable to initialize parser: %v", err)
 	}
+	parser.CollectErrors = params.CollectErrors
+	parser.DiscoverRoutes = params.DiscoverRoutes
+	parser.SortOutput = params.SortOutput
+
+	if format == "lint" {
+		// Report every problem, not only the first one
+		parser.CollectErrors = true
+	}
 
 	log.Println("Start parsing")
 
@@ -130,7 +190,9 @@ func Run(params Params) error {
 	for _, d := range dirs {
 		apifile := path.Join(d, "src", params.MainApiFile)
 		if _, err := os.Stat(apifile); err == nil {
-			parser.ParseGeneralApiInfo(apifile)
+			if err := parser.ParseGeneralApiInfo(apifile); err != nil {
+				return err
+			}
 
 			log.Debugf("Found entry point API file '%v'", apifile)
 			found = true
@@ -139,40 +201,69 @@ func Run(params Params) error {
 	}
 	if found == false {
 		if _, err := os.Stat(params.MainApiFile); err == nil {
-			parser.ParseGeneralApiInfo(params.MainApiFile)
+			if err := parser.ParseGeneralApiInfo(params.MainApiFile); err != nil {
+				return err
+			}
+		} else if pkgRealPath := parser.CheckRealPackagePath(path.Dir(params.MainApiFile)); pkgRealPath != "" {
+			// Main API file given as <import path>/<file>, e.g. inside a module
+			apifile := path.Join(pkgRealPath, path.Base(params.MainApiFile))
+			if _, err := os.Stat(apifile); err != nil {
+				return fmt.Errorf("Could not find apifile %s to parse\n", apifile)
+			}
+			if err := parser.ParseGeneralApiInfo(apifile); err != nil {
+				return err
+			}
+			log.Debugf("Found entry point API file '%v'", apifile)
 		} else {
 			apifile := path.Join(parser.GoPath, "src", params.MainApiFile)
 			return fmt.Errorf("Could not find apifile %s to parse\n", apifile)
 		}
 	}
 
-	parser.ParseApi()
+	if err := parser.ParseApi(); err != nil && format != "lint" {
+		return err
+	}
 
 	log.Println("Finish parsing")
 
-	var confirmMsg string
+	if format != "lint" {
+		for _, warning := range parser.Warnings {
+			log.Warn(warning.Error())
+		}
+	}
 
-	format := strings.ToLower(params.OutputFormat)
+	var confirmMsg string
+	// In check mode nothing is written, the files are compared with the ones at OutputSpec
+	out := &output{check: params.Check, diff: os.Stdout}
 
 	switch format {
 	case "go":
-		err = generateSwaggerDocs(parser, params.OutputSpec, false)
+		err = generateSwaggerDocs(parser, params.OutputSpec, false, out)
 		confirmMsg = "Doc file generated"
 	case "gopkg":
-		err = generateSwaggerDocs(parser, params.OutputSpec, true)
+		err = generateSwaggerDocs(parser, params.OutputSpec, true, out)
 		confirmMsg = "Doc package generated"
 	case "asciidoc":
-		err = markup.GenerateMarkup(parser, new(markup.MarkupAsciiDoc), &params.OutputSpec, ".adoc", params.ContentsTable, params.Models)
+		err = out.writeFile(markup.MarkupFileName(params.OutputSpec, ".adoc"), markup.RenderMarkup(parser, new(markup.MarkupAsciiDoc), params.ContentsTable, params.Models))
 		confirmMsg = "AsciiDoc file generated"
 	case "markdown":
-		err = markup.GenerateMarkup(parser, new(markup.MarkupMarkDown), &params.OutputSpec, ".md", params.ContentsTable, params.Models)
+		err = out.writeFile(markup.MarkupFileName(params.OutputSpec, ".md"), markup.RenderMarkup(parser, new(markup.MarkupMarkDown), params.ContentsTable, params.Models))
 		confirmMsg = "MarkDown file generated"
 	case "confluence":
-		err = markup.GenerateMarkup(parser, new(markup.MarkupConfluence), &params.OutputSpec, ".confluence", params.ContentsTable, params.Models)
+		err = out.writeFile(markup.MarkupFileName(params.OutputSpec, ".confluence"), markup.RenderMarkup(parser, new(markup.MarkupConfluence), params.ContentsTable, params.Models))
 		confirmMsg = "Confluence file generated"
 	case "swagger":
-		err = generateSwaggerUiFiles(parser, params.OutputSpec)
+		err = generateSwaggerUiFiles(parser, params.OutputSpec, encoding, out)
 		confirmMsg = "Swagger UI files generated"
+	case "swagger2":
+		err = generateSpecFile(swagger2.NewSwagger(parser), params.OutputSpec, "swagger.json", encoding, out)
+		confirmMsg = "Swagger 2.0 spec generated"
+	case "openapi3":
+		err = generateSpecFile(openapi3.NewOpenAPI(parser), params.OutputSpec, "openapi.json", encoding, out)
+		confirmMsg = "OpenAPI 3 spec generated"
+	case "lint":
+		err = lintApi(parser, os.Stdout)
+		confirmMsg = "No annotation problems found"
 	default:
 		err = fmt.Errorf("Invalid -format specified. Must be one of %v.", AVAILABLE_FORMATS)
 	}
@@ -180,6 +271,12 @@ func Run(params Params) error {
 	if err != nil {
 		return err
 	}
+	if params.Check && format != "lint" {
+		if len(out.stale) > 0 {
+			return fmt.Errorf("Generated files are out of date: %s", strings.Join(out.stale, ", "))
+		}
+		confirmMsg = "Generated files are up to date"
+	}
 	log.Println(confirmMsg)
 
 	return nil
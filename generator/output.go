@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// output receives the generated files. They are written, or in check mode only compared with the files on disk
+type output struct {
+	check bool
+	// Unified diffs of the stale files are printed to diff in check mode
+	diff  io.Writer
+	stale []string
+	// Files which were written or checked, by cleaned path
+	files map[string]bool
+}
+
+func (out *output) writeFile(filename string, data []byte) error {
+	if out.files == nil {
+		out.files = make(map[string]bool)
+	}
+	out.files[filepath.Clean(filename)] = true
+	if out.check {
+		return out.checkFile(filename, data)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0777); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filename, data, 0666); err != nil {
+		return fmt.Errorf("Can not create the %s file: %v\n", filename, err)
+	}
+	log.Printf("Wrote %v", filename)
+	return nil
+}
+
+func (out *output) checkFile(filename string, data []byte) error {
+	fromFile := filename
+	current, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		fromFile = "/dev/null"
+	} else if err != nil {
+		return fmt.Errorf("Can not read the %s file: %v\n", filename, err)
+	}
+
+	if !bytes.Equal(current, data) {
+		out.stale = append(out.stale, filename)
+		fmt.Fprint(out.diff, unifiedDiff(fromFile, filename, string(current), string(data)))
+	}
+	return nil
+}
+
+// checkExtraFiles reports the files named fileName under dir which are not generated any more,
+// like the spec of a removed resource. It only runs in check mode
+func (out *output) checkExtraFiles(dir, fileName string) error {
+	if !out.check {
+		return nil
+	}
+	if dir == "" {
+		dir = "."
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	return filepath.Walk(dir, func(filename string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || info.Name() != fileName || out.files[filepath.Clean(filename)] {
+			return err
+		}
+		current, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return fmt.Errorf("Can not read the %s file: %v\n", filename, err)
+		}
+		out.stale = append(out.stale, filename)
+		fmt.Fprint(out.diff, unifiedDiff(filename, "/dev/null", string(current), ""))
+		return nil
+	})
+}
@@ -8,9 +8,10 @@ import (
 	"github.com/yvasiyarov/swagger/generator"
 )
 
-var apiPackage = flag.String("apiPackage", "", "The package that implements the API controllers, relative to $GOPATH/src")
-var mainApiFile = flag.String("mainApiFile", "", "The file that contains the general API annotations, relative to $GOPATH/src")
+var apiPackage = flag.String("apiPackage", "", "The package that implements the API controllers: an import path (GOPATH or module) or a directory inside a module")
+var mainApiFile = flag.String("mainApiFile", "", "The file that contains the general API annotations: a path relative to $GOPATH/src, an <import path>/<file> or a file path")
 var outputFormat = flag.String("format", "go", "Output format type for the generated files: "+generator.AVAILABLE_FORMATS)
+var encoding = flag.String("encoding", "json", "Encoding of the generated specs: "+generator.AVAILABLE_ENCODINGS)
 var outputSpec = flag.String("output", "", "Output (path) for the generated file(s)")
 var controllerClass = flag.String("controllerClass", "", "Speed up parsing by specifying which receiver objects have the controller methods")
 var ignore = flag.String("ignore", "^$", "Ignore packages that satisfy this match")
@@ -18,6 +19,10 @@ var contentsTable = flag.Bool("contentsTable", true, "Generate the section Table
 var models = flag.Bool("models", true, "Generate the section models if any defined")
 var vendoringPath = flag.String("vendoringPath", "", "Override default vendor directory")
 var disableVendoring = flag.Bool("disableVendoring", false, "Disable vendor dir usage")
+var collectErrors = flag.Bool("collectErrors", false, "Report all parse errors instead of stopping at the first one")
+var discoverRoutes = flag.Bool("discoverRoutes", false, "Take the paths and methods of operations from the router registration code, @Router is used for handlers which are not found")
+var sortOutput = flag.Bool("sortOutput", false, "Order resources and apis by path and operations by method, instead of the source order")
+var check = flag.Bool("check", false, "Do not write the output, compare it with the files at -output, print a diff and fail if they are out of date")
 var enableDebug = flag.Bool("enableDebug", false, "Enable debug log output")
 
 func init() {
@@ -48,12 +53,17 @@ func main() {
 		MainApiFile:      *mainApiFile,
 		OutputFormat:     *outputFormat,
 		OutputSpec:       *outputSpec,
+		Encoding:         *encoding,
 		ControllerClass:  *controllerClass,
 		Ignore:           *ignore,
 		ContentsTable:    *contentsTable,
 		Models:           *models,
 		VendoringPath:    *vendoringPath,
 		DisableVendoring: *disableVendoring,
+		CollectErrors:    *collectErrors,
+		DiscoverRoutes:   *discoverRoutes,
+		SortOutput:       *sortOutput,
+		Check:            *check,
 	}
 
 	err := generator.Run(params)
@@ -15,6 +15,7 @@ const (
 	color_NORMAL_TEXT             = "black"
 	color_API_SECTION_HEADER_TEXT = "red"
 	color_MODEL_TEXT              = "orange"
+	color_DEPRECATED_TEXT         = "gray"
 	color_NORMAL_BACKGROUND       = "white"
 	color_GET                     = "cyan"
 	color_POST                    = "green"
@@ -35,21 +36,32 @@ type Markup interface {
 	tableRow(args ...string) string
 	tableFooter() string
 	colorSpan(content, foregroundColor, backgroundColor string) string
+	codeBlock(language, code string) string
+	deprecated(text string) string
 }
 
 func GenerateMarkup(parser *parser.Parser, markup Markup, outputSpec *string, defaultFileExtension string, tableContents bool, models bool) error {
-	var filename string
-	if *outputSpec == "" {
-		filename = path.Join("./", "API") + defaultFileExtension
-	} else {
-		filename = path.Join(*outputSpec)
-	}
-	fd, err := os.Create(filename)
+	fd, err := os.Create(MarkupFileName(*outputSpec, defaultFileExtension))
 	if err != nil {
 		return fmt.Errorf("Can not create document file: %v\n", err)
 	}
 	defer fd.Close()
 
+	fd.Write(RenderMarkup(parser, markup, tableContents, models))
+
+	return nil
+}
+
+// MarkupFileName returns outputSpec, or API with defaultFileExtension in the current dir if it is empty
+func MarkupFileName(outputSpec string, defaultFileExtension string) string {
+	if outputSpec == "" {
+		return path.Join("./", "API") + defaultFileExtension
+	}
+	return path.Join(outputSpec)
+}
+
+// RenderMarkup renders the document in memory
+func RenderMarkup(parser *parser.Parser, markup Markup, tableContents bool, models bool) []byte {
 	var buf bytes.Buffer
 
 	/***************************************************************
@@ -58,6 +70,21 @@ func GenerateMarkup(parser *parser.Parser, markup Markup, outputSpec *string, de
 	buf.WriteString(markup.sectionHeader(1, parser.Listing.Infos.Title))
 	buf.WriteString(fmt.Sprintf("%s\n\n", parser.Listing.Infos.Description))
 
+	/***************************************************************
+	* Security Definitions
+	***************************************************************/
+	if len(parser.Listing.Authorizations) > 0 {
+		buf.WriteString(markup.sectionHeader(2, "Security"))
+		buf.WriteString(markup.tableHeader(""))
+		buf.WriteString(markup.tableHeaderRow("Name", "Type", "Details", "Scopes"))
+		for _, name := range alphabeticalKeysOfAuthorizations(parser.Listing.Authorizations) {
+			authorization := parser.Listing.Authorizations[name]
+			buf.WriteString(markup.tableRow(name, authorization.Type, authorizationDetails(authorization), scopesText(authorization.Scopes)))
+		}
+		buf.WriteString(markup.tableFooter())
+		buf.WriteString("\n")
+	}
+
 	/***************************************************************
 	* Table of Contents (List of Sub-APIs)
 	***************************************************************/
@@ -80,6 +107,9 @@ func GenerateMarkup(parser *parser.Parser, markup Markup, outputSpec *string, de
 			buf.WriteString(markup.anchor(apiKey))
 		}
 		buf.WriteString(markup.sectionHeader(2, markup.colorSpan(apiKey, color_API_SECTION_HEADER_TEXT, color_NORMAL_BACKGROUND)))
+		if description := groupDescription(parser.Listing, apiDescription.ResourcePath); description != "" {
+			buf.WriteString("\n" + description + "\n\n")
+		}
 
 		buf.WriteString(markup.tableHeader(""))
 		buf.WriteString(markup.tableHeaderRow("Specification", "Value"))
@@ -102,7 +132,7 @@ func GenerateMarkup(parser *parser.Parser, markup Markup, outputSpec *string, de
 		for _, subapi := range apiDescription.Apis {
 			for _, op := range subapi.Operations {
 				pathString := strings.Replace(strings.Replace(subapi.Path, "{", "\\{", -1), "}", "\\}", -1)
-				buf.WriteString(markup.tableRow(pathString, markup.link(op.Nickname, op.HttpMethod), op.Summary))
+				buf.WriteString(markup.tableRow(pathString, deprecatedText(markup, markup.link(operationAnchor(apiKey, op), op.HttpMethod), op.Deprecated), op.Summary))
 			}
 		}
 		buf.WriteString(markup.tableFooter())
@@ -116,10 +146,16 @@ func GenerateMarkup(parser *parser.Parser, markup Markup, outputSpec *string, de
 				buf.WriteString("\n")
 				operationString := fmt.Sprintf("%s (%s)", strings.Replace(strings.Replace(subapi.Path, "{", "\\{", -1), "}", "\\}", -1), op.HttpMethod)
 				if tableContents {
-					buf.WriteString(markup.anchor(op.Nickname))
+					buf.WriteString(markup.anchor(operationAnchor(apiKey, op)))
 				}
 				buf.WriteString(markup.sectionHeader(4, markup.colorSpan("API: "+operationString, color_NORMAL_TEXT, operationColor(op.HttpMethod))))
+				if op.Deprecated {
+					buf.WriteString("\n\n" + markup.colorSpan("DEPRECATED", color_DEPRECATED_TEXT, color_NORMAL_BACKGROUND) + "\n")
+				}
 				buf.WriteString("\n\n" + op.Summary + "\n\n\n")
+				if op.Notes != "" && op.Notes != op.Summary {
+					buf.WriteString(op.Notes + "\n\n\n")
+				}
 
 				if len(op.Parameters) > 0 {
 					buf.WriteString(markup.tableHeader(""))
@@ -129,7 +165,16 @@ func GenerateMarkup(parser *parser.Parser, markup Markup, outputSpec *string, de
 						if param.Required {
 							isRequired = "Yes"
 						}
-						buf.WriteString(markup.tableRow(param.Name, param.ParamType, modelText(markup, param.DataType), param.Description, isRequired))
+						buf.WriteString(markup.tableRow(param.Name, param.ParamType, modelText(markup, param.DataType), exampleText(param.Description, param.DefaultValue, param.Example), isRequired))
+					}
+					buf.WriteString(markup.tableFooter())
+				}
+
+				if len(op.Security) > 0 {
+					buf.WriteString(markup.tableHeader(""))
+					buf.WriteString(markup.tableHeaderRow("Security", "Scopes"))
+					for _, name := range alphabeticalKeysOfScopes(op.Security) {
+						buf.WriteString(markup.tableRow(name, scopesText(op.Security[name])))
 					}
 					buf.WriteString(markup.tableFooter())
 				}
@@ -141,6 +186,25 @@ func GenerateMarkup(parser *parser.Parser, markup Markup, outputSpec *string, de
 						buf.WriteString(markup.tableRow(fmt.Sprintf("%v", msg.Code), msg.ResponseType, modelText(markup, msg.ResponseModel), msg.Message))
 					}
 					buf.WriteString(markup.tableFooter())
+
+					if hasResponseHeaders(op.ResponseMessages) {
+						buf.WriteString(markup.tableHeader(""))
+						buf.WriteString(markup.tableHeaderRow("Code", "Header", "Type", "Description"))
+						for _, msg := range op.ResponseMessages {
+							for _, header := range msg.Headers {
+								buf.WriteString(markup.tableRow(fmt.Sprintf("%v", msg.Code), header.Name, header.Type, header.Description))
+							}
+						}
+						buf.WriteString(markup.tableFooter())
+					}
+
+					for _, msg := range op.ResponseMessages {
+						for _, contentType := range alphabeticalKeysOfExamples(msg.Examples) {
+							buf.WriteString(fmt.Sprintf("Example of response %d (%s):\n\n", msg.Code, contentType))
+							buf.WriteString(markup.codeBlock(codeLanguage(contentType), msg.Examples[contentType]))
+							buf.WriteString("\n")
+						}
+					}
 				}
 			}
 		}
@@ -159,11 +223,14 @@ func GenerateMarkup(parser *parser.Parser, markup Markup, outputSpec *string, de
 					buf.WriteString(markup.anchor(modelKey))
 				}
 				buf.WriteString(markup.sectionHeader(4, markup.colorSpan(shortModelName(modelKey), color_MODEL_TEXT, color_NORMAL_BACKGROUND)))
+				if model.Deprecated {
+					buf.WriteString(markup.colorSpan("DEPRECATED", color_DEPRECATED_TEXT, color_NORMAL_BACKGROUND) + "\n\n")
+				}
 				buf.WriteString(markup.tableHeader(""))
 				buf.WriteString(markup.tableHeaderRow("Field Name (alphabetical)", "Field Type", "Description"))
 				for _, fieldName := range alphabeticalKeysOfFields(model.Properties) {
 					fieldProps := model.Properties[fieldName]
-					buf.WriteString(markup.tableRow(fieldName, fieldProps.Type, fieldProps.Description))
+					buf.WriteString(markup.tableRow(deprecatedText(markup, fieldName, fieldProps.Deprecated), propertyTypeText(fieldProps), exampleText(fieldProps.Description, "", fieldProps.Example)))
 				}
 				buf.WriteString(markup.tableFooter())
 			}
@@ -171,9 +238,104 @@ func GenerateMarkup(parser *parser.Parser, markup Markup, outputSpec *string, de
 		}
 	}
 
-	fd.WriteString(buf.String())
+	return buf.Bytes()
+}
 
-	return nil
+// propertyTypeText renders maps with their value type, map[string]Type
+func propertyTypeText(property *parser.ModelProperty) string {
+	if property.AdditionalProperties != nil {
+		return "map[string]" + propertyTypeText(property.AdditionalProperties)
+	}
+	return property.Type
+}
+
+// authorizationDetails renders where API keys are passed and the endpoints of OAuth2 flows
+func authorizationDetails(authorization *parser.SecurityDefinition) string {
+	switch authorization.Type {
+	case "apiKey":
+		return fmt.Sprintf("%s in %s", authorization.Keyname, authorization.PassAs)
+	case "oauth2":
+		details := []string{authorization.Flow}
+		for _, url := range []string{authorization.AuthorizationUrl, authorization.TokenUrl} {
+			if url != "" {
+				details = append(details, url)
+			}
+		}
+		return strings.Join(details, " ")
+	}
+	return ""
+}
+
+func scopesText(scopes []parser.Scope) string {
+	names := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		names = append(names, scope.Scope)
+	}
+	return strings.Join(names, ", ")
+}
+
+func hasResponseHeaders(responses []parser.ResponseMessage) bool {
+	for _, msg := range responses {
+		if len(msg.Headers) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// groupDescription returns the description of the resource or the tag the operations are grouped by
+func groupDescription(listing *parser.ResourceListing, resourcePath string) string {
+	for _, ref := range listing.Apis {
+		if ref.Path == resourcePath {
+			return ref.Description
+		}
+	}
+	return ""
+}
+
+// operationAnchor returns the anchor of the operation details.
+// Operations with several tags are rendered in each group, so their anchors include the group
+func operationAnchor(group string, op *parser.Operation) string {
+	if len(op.Tags) > 1 {
+		return group + "-" + op.Nickname
+	}
+	return op.Nickname
+}
+
+// deprecatedText strikes the text through if it is deprecated
+func deprecatedText(markup Markup, text string, deprecated bool) string {
+	if deprecated {
+		return markup.deprecated(text)
+	}
+	return text
+}
+
+// exampleText appends the default value and the example to a description
+func exampleText(description, defaultValue, example string) string {
+	details := make([]string, 0, 2)
+	if defaultValue != "" {
+		details = append(details, "default: "+defaultValue)
+	}
+	if example != "" {
+		details = append(details, "example: "+example)
+	}
+	if len(details) == 0 {
+		return description
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s (%s)", description, strings.Join(details, ", ")))
+}
+
+// codeLanguage returns the language of code blocks with examples of the content type
+func codeLanguage(contentType string) string {
+	switch {
+	case strings.Contains(contentType, "json"):
+		return "json"
+	case strings.Contains(contentType, "xml"):
+		return "xml"
+	case strings.Contains(contentType, "html"):
+		return "html"
+	}
+	return ""
 }
 
 func shortModelName(longModelName string) string {
@@ -221,6 +383,31 @@ func alphabeticalKeysOfModels(m map[string]*parser.Model) []string {
 	sort.Strings(keys)
 	return keys
 }
+func alphabeticalKeysOfAuthorizations(m map[string]*parser.SecurityDefinition) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+func alphabeticalKeysOfScopes(m map[string][]parser.Scope) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+func alphabeticalKeysOfExamples(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func alphabeticalKeysOfFields(m map[string]*parser.ModelProperty) []string {
 	keys := make([]string, len(m))
 	i := 0
@@ -71,3 +71,16 @@ func (this *MarkupAsciiDoc) tableRow(args ...string) string {
 func (this *MarkupAsciiDoc) colorSpan(content, foregroundColor, backgroundColor string) string {
 	return fmt.Sprintf("[%s,%s-background]#%s#", foregroundColor, backgroundColor, content)
 }
+
+// codeBlock issues a listing block, with source highlighting if the language is known
+func (this *MarkupAsciiDoc) codeBlock(language, code string) string {
+	if language == "" {
+		return "----\n" + code + "\n----\n"
+	}
+	return fmt.Sprintf("[source,%s]\n----\n%s\n----\n", language, code)
+}
+
+// deprecated strikes the text through
+func (this *MarkupAsciiDoc) deprecated(text string) string {
+	return "[line-through]#" + text + "#"
+}
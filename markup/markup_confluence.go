@@ -77,3 +77,16 @@ func (this *MarkupConfluence) colorSpan(content, foregroundColor, backgroundColo
 	return fmt.Sprintf("{color:%s}{bgcolor:%s}%s{bgcolor}{color}", foregroundColor, backgroundColor, content)
 
 }
+
+// codeBlock issues a code macro
+func (this *MarkupConfluence) codeBlock(language, code string) string {
+	if language == "" {
+		return "{code}\n" + code + "\n{code}\n"
+	}
+	return fmt.Sprintf("{code:language=%s}\n%s\n{code}\n", language, code)
+}
+
+// deprecated strikes the text through
+func (this *MarkupConfluence) deprecated(text string) string {
+	return "-" + text + "-"
+}
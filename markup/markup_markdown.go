@@ -70,3 +70,13 @@ func (this *MarkupMarkDown) tableFooter() string {
 func (this *MarkupMarkDown) colorSpan(content, foregroundColor, backgroundColor string) string {
 	return content
 }
+
+// codeBlock issues a fenced code block
+func (this *MarkupMarkDown) codeBlock(language, code string) string {
+	return "```" + language + "\n" + code + "\n```\n"
+}
+
+// deprecated strikes the text through
+func (this *MarkupMarkDown) deprecated(text string) string {
+	return "~~" + text + "~~"
+}
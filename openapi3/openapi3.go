@@ -0,0 +1,266 @@
+package openapi3
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yvasiyarov/swagger/convert"
+	"github.com/yvasiyarov/swagger/parser"
+)
+
+// NewOpenAPI converts the Swagger 1.2 resource listing and API declarations collected by the parser
+// into a single OpenAPI 3 document
+func NewOpenAPI(p *parser.Parser) *OpenAPI {
+	spec := &OpenAPI{
+		OpenAPI: OpenAPIVersion,
+		Info:    convert.NewInfo(p.Listing),
+		Paths:   make(map[string]*PathItem),
+		Tags:    convert.NewTags(p.Listing),
+		Components: &Components{
+			SecuritySchemes: make(map[string]*SecurityScheme),
+		},
+		schemas: &convert.Schemas{
+			RefPrefix: "#/components/schemas/",
+			// File uploads are binary strings
+			File: Schema{Type: "string", Format: "binary"},
+		},
+	}
+
+	for name, authorization := range p.Listing.Authorizations {
+		spec.Components.SecuritySchemes[name] = newSecurityScheme(authorization)
+	}
+
+	// Template placeholders like {{.}} are not valid server urls
+	if basePath := p.Listing.BasePath; basePath != "" && !strings.Contains(basePath, "{{") {
+		spec.Servers = []*Server{{Url: basePath}}
+	}
+	environments := make([]string, 0, len(p.Listing.Environments))
+	for name := range p.Listing.Environments {
+		environments = append(environments, name)
+	}
+	sort.Strings(environments)
+	for _, name := range environments {
+		spec.Servers = append(spec.Servers, &Server{Url: p.Listing.Environments[name], Description: name})
+	}
+
+	spec.schemas.AddModels(p)
+	spec.Components.Schemas = spec.schemas.Models
+
+	convert.Operations(p, func(resource, path string, op *parser.Operation) {
+		pathItem, ok := spec.Paths[path]
+		if !ok {
+			pathItem = &PathItem{}
+			spec.Paths[path] = pathItem
+		}
+		pathItem.SetOperation(op.HttpMethod, spec.newOperation(resource, op))
+	})
+
+	if len(spec.Components.SecuritySchemes) == 0 {
+		spec.Components.SecuritySchemes = nil
+	}
+	if len(spec.Components.Schemas) == 0 && spec.Components.SecuritySchemes == nil {
+		spec.Components = nil
+	}
+
+	return spec
+}
+
+func (spec *OpenAPI) newOperation(resource string, op *parser.Operation) *Operation {
+	operation := &Operation{
+		Tags:        op.Tags,
+		Summary:     op.Summary,
+		Description: convert.Description(op),
+		OperationId: op.Nickname,
+		Responses:   make(map[string]*Response),
+	}
+
+	if len(operation.Tags) == 0 {
+		operation.Tags = []string{resource}
+	}
+
+	var bodyParams, formParams []parser.Parameter
+	for _, param := range op.Parameters {
+		switch param.ParamType {
+		case "body":
+			bodyParams = append(bodyParams, param)
+		case "form":
+			formParams = append(formParams, param)
+		default:
+			operation.Parameters = append(operation.Parameters, spec.newParameter(param))
+		}
+	}
+	operation.RequestBody = spec.newRequestBody(op.Consumes, bodyParams, formParams)
+
+	for _, msg := range op.ResponseMessages {
+		response := &Response{
+			Description: convert.ResponseDescription(msg),
+		}
+		if msg.ResponseModel != "" {
+			schema := spec.schemas.SchemaForType(msg.ResponseModel)
+			if msg.ResponseType == "array" {
+				schema = &Schema{Type: "array", Items: schema}
+			}
+			response.Content = newContent(op.Produces, ContentTypeDefault, schema)
+		}
+		for _, header := range msg.Headers {
+			if response.Headers == nil {
+				response.Headers = make(map[string]*Header)
+			}
+			response.Headers[header.Name] = &Header{
+				Description: header.Description,
+				Schema:      &Schema{Type: header.Type, Format: header.Format},
+			}
+		}
+		for contentType, example := range msg.Examples {
+			if response.Content == nil {
+				response.Content = make(map[string]*MediaType)
+			}
+			if response.Content[contentType] == nil {
+				response.Content[contentType] = &MediaType{}
+			}
+			response.Content[contentType].Example = convert.ResponseExample(contentType, example)
+		}
+		operation.Responses[strconv.Itoa(msg.Code)] = response
+	}
+
+	if len(operation.Responses) == 0 {
+		operation.Responses[convert.DefaultResponseCode] = &Response{Description: convert.DefaultResponseDescription}
+	}
+
+	operation.Security = convert.SecurityRequirements(op.Security)
+	operation.Deprecated = op.Deprecated
+
+	return operation
+}
+
+func newSecurityScheme(authorization *parser.SecurityDefinition) *SecurityScheme {
+	switch authorization.Type {
+	case "basicAuth":
+		return &SecurityScheme{Type: "http", Scheme: "basic"}
+	case "apiKey":
+		return &SecurityScheme{Type: "apiKey", Name: authorization.Keyname, In: authorization.PassAs}
+	}
+
+	flow := &OAuthFlow{
+		AuthorizationUrl: authorization.AuthorizationUrl,
+		TokenUrl:         authorization.TokenUrl,
+		Scopes:           make(map[string]string),
+	}
+	for _, scope := range authorization.Scopes {
+		flow.Scopes[scope.Scope] = scope.Description
+	}
+	// Swagger 2.0 flow names
+	flows := &OAuthFlows{}
+	switch authorization.Flow {
+	case "implicit":
+		flow.TokenUrl = ""
+		flows.Implicit = flow
+	case "password":
+		flow.AuthorizationUrl = ""
+		flows.Password = flow
+	case "application":
+		flow.AuthorizationUrl = ""
+		flows.ClientCredentials = flow
+	default:
+		flows.AuthorizationCode = flow
+	}
+	return &SecurityScheme{Type: "oauth2", Flows: flows}
+}
+
+func (spec *OpenAPI) newParameter(param parser.Parameter) *Parameter {
+	parameter := &Parameter{
+		Name:        param.Name,
+		In:          param.ParamType,
+		Description: param.Description,
+		Required:    param.Required,
+	}
+	if param.ParamType == "path" {
+		// Path parameters are always required
+		parameter.Required = true
+	}
+
+	parameter.Schema = spec.schemas.SchemaForType(param.DataType)
+	if parameter.Schema.Ref != "" || parameter.Schema.Type == "object" {
+		// Models can not be serialised into paths, queries or headers, they are passed as strings
+		parameter.Schema = &Schema{Type: "string"}
+	}
+	for _, value := range param.Enum {
+		parameter.Schema.Enum = append(parameter.Schema.Enum, convert.EnumValue(parameter.Schema.Type, value))
+	}
+	if param.DefaultValue != "" {
+		parameter.Schema.Default = convert.ExampleValue(parameter.Schema, param.DefaultValue)
+	}
+	if param.Example != "" {
+		parameter.Example = convert.ExampleValue(parameter.Schema, param.Example)
+	}
+
+	return parameter
+}
+
+// newRequestBody joins @Param body and form parameters into a single request body.
+// Form parameters become the properties of an object schema
+func (spec *OpenAPI) newRequestBody(consumes []string, bodyParams, formParams []parser.Parameter) *RequestBody {
+	if len(bodyParams) == 0 && len(formParams) == 0 {
+		return nil
+	}
+
+	requestBody := &RequestBody{}
+	if len(bodyParams) > 0 {
+		// Only one body parameter is allowed, the first one wins. The parser warns about the other body and form params
+		requestBody.Description = bodyParams[0].Description
+		requestBody.Required = bodyParams[0].Required
+		schema := spec.schemas.SchemaForType(bodyParams[0].DataType)
+		requestBody.Content = newContent(consumes, ContentTypeDefault, schema)
+		if bodyParams[0].Example != "" {
+			for _, media := range requestBody.Content {
+				media.Example = convert.ExampleValue(schema, bodyParams[0].Example)
+			}
+		}
+		return requestBody
+	}
+
+	schema := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema, len(formParams)),
+	}
+	defaultContentType := ContentTypeForm
+	for _, param := range formParams {
+		propertySchema := spec.schemas.SchemaForType(param.DataType)
+		if propertySchema.Format == "binary" {
+			defaultContentType = parser.ContentTypeMultiPartFormData
+		}
+		if propertySchema.Ref == "" {
+			propertySchema.Description = param.Description
+			for _, value := range param.Enum {
+				propertySchema.Enum = append(propertySchema.Enum, convert.EnumValue(propertySchema.Type, value))
+			}
+			if param.DefaultValue != "" {
+				propertySchema.Default = convert.ExampleValue(propertySchema, param.DefaultValue)
+			}
+			if param.Example != "" {
+				propertySchema.Example = convert.ExampleValue(propertySchema, param.Example)
+			}
+		}
+		schema.Properties[param.Name] = propertySchema
+		if param.Required {
+			schema.Required = append(schema.Required, param.Name)
+			requestBody.Required = true
+		}
+	}
+	requestBody.Content = newContent(consumes, defaultContentType, schema)
+
+	return requestBody
+}
+
+func newContent(contentTypes []string, defaultContentType string, schema *Schema) map[string]*MediaType {
+	if len(contentTypes) == 0 {
+		contentTypes = []string{defaultContentType}
+	}
+
+	content := make(map[string]*MediaType, len(contentTypes))
+	for _, contentType := range contentTypes {
+		content[contentType] = &MediaType{Schema: schema}
+	}
+	return content
+}
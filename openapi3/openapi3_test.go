@@ -0,0 +1,194 @@
+package openapi3_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/yvasiyarov/swagger/openapi3"
+	"github.com/yvasiyarov/swagger/parser"
+)
+
+type OpenAPISuite struct {
+	suite.Suite
+	parser *parser.Parser
+}
+
+func (suite *OpenAPISuite) SetupTest() {
+	suite.parser = &parser.Parser{
+		Listing: &parser.ResourceListing{
+			ApiVersion: "1.0.0",
+			BasePath:   "http://127.0.0.1:3000/v1",
+			Infos: parser.Infomation{
+				Title: "Test API",
+			},
+		},
+		TopLevelApis: make(map[string]*parser.ApiDeclaration),
+	}
+}
+
+func (suite *OpenAPISuite) addOperation(comments ...string) *parser.Operation {
+	op := parser.NewOperation(suite.parser, "test")
+	for _, comment := range comments {
+		assert.Nil(suite.T(), op.ParseComment(comment), "Can not parse operation comment")
+	}
+	suite.parser.AddOperation(op)
+	return op
+}
+
+func (suite *OpenAPISuite) TestInfo() {
+	spec := openapi3.NewOpenAPI(suite.parser)
+
+	assert.Equal(suite.T(), openapi3.OpenAPIVersion, spec.OpenAPI, "OpenAPI version not set")
+	assert.Equal(suite.T(), "Test API", spec.Info.Title, "Title not converted")
+	assert.Equal(suite.T(), "1.0.0", spec.Info.Version, "Version not converted")
+	assert.Len(suite.T(), spec.Servers, 1, "Servers not converted")
+	assert.Equal(suite.T(), "http://127.0.0.1:3000/v1", spec.Servers[0].Url, "Server url not converted")
+	assert.Nil(suite.T(), spec.Components, "Empty components should be omitted")
+}
+
+func (suite *OpenAPISuite) TestEnvironments() {
+	assert.NoError(suite.T(), suite.parser.ParseEnvironmentComment("prod https://api.example.com/v1"), "Can not parse environment comment")
+	assert.NoError(suite.T(), suite.parser.ParseEnvironmentComment("dev http://127.0.0.1:3000/v1"), "Can not parse environment comment")
+
+	spec := openapi3.NewOpenAPI(suite.parser)
+	assert.Equal(suite.T(), []*openapi3.Server{
+		{Url: "http://127.0.0.1:3000/v1"},
+		{Url: "http://127.0.0.1:3000/v1", Description: "dev"},
+		{Url: "https://api.example.com/v1", Description: "prod"},
+	}, spec.Servers, "Environments not converted to servers")
+}
+
+func (suite *OpenAPISuite) TestFormRequestBody() {
+	suite.addOperation(
+		"// @Title uploadAvatar",
+		"// @Produce json,xml",
+		"// @Param user_id path int true \"User ID\"",
+		"// @Param avatar form file true \"Avatar image\"",
+		"// @Param caption form string false \"Caption\"",
+		"// @Success 200 {object} string",
+		"// @Header 200 {string} ETag \"Version of the avatar\"",
+		"// @Router /user/{user_id}/avatar [post]",
+	)
+
+	spec := openapi3.NewOpenAPI(suite.parser)
+	op := spec.Paths["/user/{user_id}/avatar"].Post
+	assert.NotNil(suite.T(), op, "Operation not bound to its http method")
+
+	assert.Len(suite.T(), op.Parameters, 1, "Only path, query and header params are parameters")
+	assert.Equal(suite.T(), "integer", op.Parameters[0].Schema.Type, "Parameter schema not converted")
+
+	body := op.RequestBody
+	assert.NotNil(suite.T(), body, "Form params not converted to request body")
+	assert.True(suite.T(), body.Required, "Request body with required fields must be required")
+	media, ok := body.Content[parser.ContentTypeMultiPartFormData]
+	if !ok {
+		suite.T().Fatalf("File upload must be multipart: %#v", body.Content)
+	}
+	assert.Equal(suite.T(), []string{"avatar"}, media.Schema.Required, "Required form fields not converted")
+	assert.Equal(suite.T(), "binary", media.Schema.Properties["avatar"].Format, "File form field not converted")
+	assert.Equal(suite.T(), "string", media.Schema.Properties["caption"].Type, "Form field not converted")
+
+	response := op.Responses["200"]
+	assert.Len(suite.T(), response.Content, 2, "Response not keyed by produced content types")
+	assert.Equal(suite.T(), "string", response.Content[parser.ContentTypeXml].Schema.Type, "Response schema not converted")
+	if header, ok := response.Headers["ETag"]; assert.True(suite.T(), ok, "Response headers not converted") {
+		assert.Equal(suite.T(), "Version of the avatar", header.Description, "Header description not converted")
+		assert.Equal(suite.T(), "string", header.Schema.Type, "Header schema not converted")
+	}
+}
+
+func (suite *OpenAPISuite) TestBodyRequestBody() {
+	op := suite.addOperation(
+		"// @Accept json",
+		"// @Router /order [put]",
+	)
+	op.Parameters = append(op.Parameters, parser.Parameter{Name: "order", ParamType: "body", DataType: "test.Order", Required: true})
+
+	maxLength := int64(8)
+	order := parser.NewModel(suite.parser)
+	order.Id = "test.Order"
+	order.Deprecated = true
+	order.Properties = map[string]*parser.ModelProperty{
+		"id":   {Type: "int64"},
+		"code": {Type: "string", Pattern: "^[A-Z]+$", MaxLength: &maxLength},
+		"tags": {Type: "object", AdditionalProperties: &parser.ModelProperty{Type: "string"}},
+	}
+	op.Models = append(op.Models, order)
+	suite.parser.TopLevelApis["order"].AddModels(op)
+
+	spec := openapi3.NewOpenAPI(suite.parser)
+	assert.Len(suite.T(), spec.Components.Schemas, 1, "Models not converted to component schemas")
+	assert.True(suite.T(), spec.Components.Schemas["test.Order"].Deprecated, "Deprecated model not converted")
+	assert.Equal(suite.T(), "int64", spec.Components.Schemas["test.Order"].Properties["id"].Format, "Model property not converted")
+	assert.Equal(suite.T(), "^[A-Z]+$", spec.Components.Schemas["test.Order"].Properties["code"].Pattern, "Model property constraints not converted")
+	assert.Equal(suite.T(), int64(8), *spec.Components.Schemas["test.Order"].Properties["code"].MaxLength, "Model property constraints not converted")
+	assert.Equal(suite.T(), "string", spec.Components.Schemas["test.Order"].Properties["tags"].AdditionalProperties.Type, "Map property not converted")
+
+	body := spec.Paths["/order"].Put.RequestBody
+	assert.NotNil(suite.T(), body, "Body param not converted to request body")
+	assert.True(suite.T(), body.Required, "Required body not converted")
+	assert.Equal(suite.T(), "#/components/schemas/test.Order", body.Content[parser.ContentTypeJson].Schema.Ref, "Body schema not converted")
+	assert.Equal(suite.T(), "Successful operation", spec.Paths["/order"].Put.Responses["default"].Description, "Default response not added")
+}
+
+func (suite *OpenAPISuite) TestExamples() {
+	op := suite.addOperation(
+		"// @Produce json",
+		"// @Param limit query int false \"Limit\" default(10) example(20)",
+		"// @Param tag form string false \"Tag\" example(new)",
+		"// @Success 200 {object} string",
+		"// @Router /orders [post]",
+	)
+	op.ResponseMessages[0].Examples = map[string]string{
+		parser.ContentTypeJson:  `["a", "b"]`,
+		parser.ContentTypePlain: "a, b",
+	}
+
+	spec := openapi3.NewOpenAPI(suite.parser)
+	post := spec.Paths["/orders"].Post
+	assert.Equal(suite.T(), int64(10), post.Parameters[0].Schema.Default, "Param default not converted")
+	assert.Equal(suite.T(), int64(20), post.Parameters[0].Example, "Param example not converted")
+	assert.Equal(suite.T(), "new", post.RequestBody.Content[openapi3.ContentTypeForm].Schema.Properties["tag"].Example, "Form param example not converted")
+
+	content := post.Responses["200"].Content
+	assert.Equal(suite.T(), []interface{}{"a", "b"}, content[parser.ContentTypeJson].Example, "Response example not decoded")
+	assert.Equal(suite.T(), "string", content[parser.ContentTypeJson].Schema.Type, "Response schema must be kept")
+	if plain, ok := content[parser.ContentTypePlain]; assert.True(suite.T(), ok, "Content types of examples not added") {
+		assert.Equal(suite.T(), "a, b", plain.Example, "Response example not converted")
+	}
+}
+
+func (suite *OpenAPISuite) TestSecurity() {
+	var authorization *parser.SecurityDefinition
+	for _, comment := range []string{
+		"@SecurityDefinitions.basic BasicAuth",
+		"@SecurityDefinitions.apikey ApiKeyAuth",
+		"@In query",
+		"@Name token",
+		"@SecurityDefinitions.oauth2.implicit OAuth2",
+		"@AuthorizationUrl https://example.com/oauth/authorize",
+		"@Scope.admin Grants admin access",
+	} {
+		var err error
+		authorization, err = suite.parser.ParseSecurityDefinitionComment(authorization, comment)
+		assert.NoError(suite.T(), err, "Can not parse security definition")
+	}
+	suite.addOperation("// @Security OAuth2 admin", "// @Security ApiKeyAuth", "// @Router /admin [get]")
+
+	spec := openapi3.NewOpenAPI(suite.parser)
+	schemes := spec.Components.SecuritySchemes
+	assert.Equal(suite.T(), &openapi3.SecurityScheme{Type: "http", Scheme: "basic"}, schemes["BasicAuth"], "Basic auth not converted")
+	assert.Equal(suite.T(), &openapi3.SecurityScheme{Type: "apiKey", Name: "token", In: "query"}, schemes["ApiKeyAuth"], "API key not converted")
+	assert.Equal(suite.T(), &openapi3.SecurityScheme{Type: "oauth2", Flows: &openapi3.OAuthFlows{
+		Implicit: &openapi3.OAuthFlow{
+			AuthorizationUrl: "https://example.com/oauth/authorize",
+			Scopes:           map[string]string{"admin": "Grants admin access"},
+		},
+	}}, schemes["OAuth2"], "OAuth2 not converted")
+	assert.Equal(suite.T(), []map[string][]string{{"ApiKeyAuth": {}}, {"OAuth2": {"admin"}}}, spec.Paths["/admin"].Get.Security, "Security requirements not converted")
+}
+
+func TestOpenAPISuite(t *testing.T) {
+	suite.Run(t, &OpenAPISuite{})
+}
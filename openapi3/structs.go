@@ -0,0 +1,111 @@
+package openapi3
+
+import "github.com/yvasiyarov/swagger/convert"
+
+const OpenAPIVersion = "3.0.3"
+
+// Media types used when an operation does not declare @Accept or @Produce
+const (
+	ContentTypeDefault = "application/json"
+	ContentTypeForm    = "application/x-www-form-urlencoded"
+)
+
+// https://spec.openapis.org/oas/v3.0.3#openapi-object
+type OpenAPI struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []*Server            `json:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components *Components          `json:"components,omitempty"`
+	Tags       []*Tag               `json:"tags,omitempty"`
+
+	schemas *convert.Schemas
+}
+
+type (
+	Info    = convert.Info
+	Contact = convert.Contact
+	License = convert.License
+	Tag     = convert.Tag
+)
+
+type Server struct {
+	Url         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#security-scheme-object
+type SecurityScheme struct {
+	Type   string      `json:"type"`             // http, apiKey or oauth2
+	Scheme string      `json:"scheme,omitempty"` // basic
+	Name   string      `json:"name,omitempty"`
+	In     string      `json:"in,omitempty"` // query or header
+	Flows  *OAuthFlows `json:"flows,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#oauth-flows-object
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `json:"implicit,omitempty"`
+	Password          *OAuthFlow `json:"password,omitempty"`
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+}
+
+type OAuthFlow struct {
+	AuthorizationUrl string            `json:"authorizationUrl,omitempty"`
+	TokenUrl         string            `json:"tokenUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes"`
+}
+
+type PathItem = convert.PathItem[Operation]
+
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	OperationId string                `json:"operationId,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+}
+
+type Parameter struct {
+	Name        string      `json:"name"`
+	In          string      `json:"in"` // path,query,header,cookie
+	Description string      `json:"description,omitempty"`
+	Required    bool        `json:"required"`
+	Schema      *Schema     `json:"schema,omitempty"`
+	Example     interface{} `json:"example,omitempty"`
+}
+
+type RequestBody struct {
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+	Content     map[string]*MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema  *Schema     `json:"schema,omitempty"`
+	Example interface{} `json:"example,omitempty"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#header-object
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type Schema = convert.Schema
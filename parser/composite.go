@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// fieldOverride replaces the type of a field of a model in a composite annotation type
+type fieldOverride struct {
+	Field, Type string
+}
+
+// splitFieldOverrides splits a composite annotation type, Envelope{data=[]model.User,meta=model.Meta},
+// into the model name and the fields whose type is overridden. Other names are returned unchanged
+func splitFieldOverrides(typeName string) (string, []fieldOverride) {
+	start := strings.Index(typeName, "{")
+	if start <= 0 || !strings.HasSuffix(typeName, "}") {
+		return typeName, nil
+	}
+
+	var overrides []fieldOverride
+	depth, overrideStart := 0, start+1
+	for i := start; i < len(typeName); i++ {
+		switch typeName[i] {
+		case '[', '{':
+			depth++
+		case ']', '}':
+			depth--
+			if depth == 0 && i != len(typeName)-1 {
+				return typeName, nil
+			}
+		}
+		if (typeName[i] == ',' && depth == 1) || depth == 0 {
+			override := strings.SplitN(typeName[overrideStart:i], "=", 2)
+			if len(override) != 2 {
+				return typeName, nil
+			}
+			overrides = append(overrides, fieldOverride{strings.TrimSpace(override[0]), strings.TrimSpace(override[1])})
+			overrideStart = i + 1
+		}
+	}
+	return typeName[:start], overrides
+}
+
+// registerComposite registers a copy of the model with the types of the fields overridden,
+// so a shared envelope documents the concrete data of every operation
+func (operation *Operation) registerComposite(modelName string, overrides []fieldOverride) (string, error) {
+	model := NewModel(operation.parser)
+	err, innerModels := model.ParseModel(modelName, operation.parser.CurrentPackage, map[string]bool{})
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(overrides))
+	for _, override := range overrides {
+		property, ok := model.Properties[override.Field]
+		if !ok {
+			return "", fmt.Errorf("Can not find field %s of model %s", override.Field, modelName)
+		}
+
+		overridden := NewModelProperty()
+		overridden.Description = property.Description
+		overridden.SetType(override.Type)
+		if swaggerType, format, ok := operation.parser.MarshalerType(overridden.ValueType()); ok {
+			overridden.SetValueType(swaggerType, format)
+		} else if valueType := overridden.ValueType(); !IsBasicType(valueType) {
+			modelId, err := operation.registerType(valueType)
+			if err != nil {
+				return "", err
+			}
+			overridden.ReplaceValueType(valueType, modelId)
+		}
+		model.Properties[override.Field] = overridden
+		names = append(names, override.Field+"_"+compositeTypeName(override.Type))
+	}
+	model.Id += "_" + strings.Join(names, "_")
+
+	operation.Models = append(operation.Models, model)
+	operation.Models = append(operation.Models, innerModels...)
+	return model.Id, nil
+}
+
+var qualifiedTypeName = regexp.MustCompile(`[\w\-\.\/]+\.(\w+)`)
+
+// compositeTypeName turns an annotation type into a part of a model id: []model.User becomes array_User
+func compositeTypeName(typeName string) string {
+	typeName = qualifiedTypeName.ReplaceAllString(typeName, "$1")
+	typeName = strings.NewReplacer("[]", "array_", "[", "_", "{", "_", ",", "_", "=", "_", "]", "", "}", "", "*", "").Replace(typeName)
+	return strings.Trim(typeName, "_")
+}
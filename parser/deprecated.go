@@ -0,0 +1,23 @@
+package parser
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// isDeprecated reports whether a paragraph of the doc comment starts with "Deprecated: ",
+// the Go convention for identifiers which should not be used anymore
+func isDeprecated(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	paragraphStart := true
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if paragraphStart && strings.HasPrefix(line, "Deprecated: ") {
+			return true
+		}
+		paragraphStart = line == ""
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// Kinds of @Description blocks, the comment lines which follow them are part of the description
+const (
+	descriptionText     = "text"
+	descriptionMarkdown = "markdown"
+)
+
+// parseDescriptionLine appends a continuation line of @Description to the notes.
+// It returns false if the line is not part of a description
+func (operation *Operation) parseDescriptionLine(comment string) bool {
+	if operation.descriptionBlock == "" {
+		return false
+	}
+	commentLine := strings.TrimSpace(strings.TrimLeft(comment, "/"))
+	if strings.HasPrefix(commentLine, "@") {
+		operation.descriptionBlock = ""
+		return false
+	}
+
+	if operation.descriptionBlock == descriptionMarkdown {
+		// Indentation is meaningful in Markdown, only the space after the comment marker is removed
+		operation.appendNotes(strings.TrimRight(strings.TrimPrefix(strings.TrimPrefix(comment, "//"), " "), " \t"))
+	} else {
+		operation.appendNotes(commentLine)
+	}
+	return true
+}
+
+// setDescription starts a description, the first line of it is the summary unless @Summary sets one
+func (operation *Operation) setDescription(text, block string) {
+	operation.appendNotes(text)
+	if operation.Summary == "" {
+		operation.Summary = text
+	}
+	operation.descriptionBlock = block
+}
+
+// appendNotes adds a line to the notes. Blank lines separate paragraphs, they are kept only between lines of text
+func (operation *Operation) appendNotes(line string) {
+	if line == "" {
+		if operation.Notes != "" {
+			operation.blankLines++
+		}
+		return
+	}
+	if operation.Notes != "" {
+		operation.Notes += strings.Repeat("\n", operation.blankLines+1)
+	}
+	operation.Notes += line
+	operation.blankLines = 0
+}
+
+// @Description.file docs/orders.md
+func (operation *Operation) ParseDescriptionFileComment(fileName string) error {
+	if fileName == "" {
+		return fmt.Errorf("Description file name is missing, skipped.")
+	}
+	content, err := operation.readPackageFile(fileName)
+	if err != nil {
+		return fmt.Errorf("Can not read description file: %v", err)
+	}
+	operation.appendNotes(strings.TrimSpace(string(content)))
+	return nil
+}
+
+// readPackageFile reads a file referenced by an annotation.
+// Relative file names are relative to the directory of the package
+func (operation *Operation) readPackageFile(fileName string) ([]byte, error) {
+	if !filepath.IsAbs(fileName) {
+		fileName = filepath.Join(operation.parser.CheckRealPackagePath(operation.packageName), fileName)
+	}
+	return ioutil.ReadFile(fileName)
+}
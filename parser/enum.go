@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// parseConstDeclaration collects the constants of named types, e.g. const (StatusActive Status = "active").
+// Their values are the enum of the type
+func (parser *Parser) parseConstDeclaration(pkgRealPath string, decl *ast.GenDecl) {
+	var typeName string
+	for _, spec := range decl.Specs {
+		valueSpec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+
+		// Specs without type and values repeat the previous one (iota)
+		if valueSpec.Type != nil {
+			typeName = ""
+			if ident, ok := valueSpec.Type.(*ast.Ident); ok {
+				typeName = ident.Name
+			}
+		} else if len(valueSpec.Values) > 0 {
+			// Typed by conversion: StatusActive = Status("active")
+			typeName = ""
+			if call, ok := valueSpec.Values[0].(*ast.CallExpr); ok {
+				if ident, ok := call.Fun.(*ast.Ident); ok {
+					typeName = ident.Name
+				}
+			}
+		}
+		if typeName == "" || IsBasicType(typeName) {
+			continue
+		}
+
+		for _, name := range valueSpec.Names {
+			if name.Name != "_" {
+				parser.TypeConstants[pkgRealPath][typeName] = append(parser.TypeConstants[pkgRealPath][typeName], name)
+			}
+		}
+	}
+}
+
+// GetTypeEnum returns the values of the constants of a named type in declaration order,
+// or nil if the type has no constants
+func (parser *Parser) GetTypeEnum(typeName, packageName string) []string {
+	pkgRealPath := parser.CheckRealPackagePath(packageName)
+	constants := parser.TypeConstants[pkgRealPath][typeName]
+	if len(constants) == 0 {
+		return nil
+	}
+
+	// Values are computed by the type checker, so iota and constant expressions are supported
+	info := parser.TypesInfo(packageName)
+	if info == nil {
+		return nil
+	}
+
+	var enum []string
+	for _, ident := range constants {
+		c, ok := info.Defs[ident].(*types.Const)
+		if !ok {
+			continue
+		}
+		if named, ok := c.Type().(*types.Named); !ok || named.Obj().Name() != typeName {
+			continue
+		}
+		enum = append(enum, constantString(c.Val()))
+	}
+	return enum
+}
+
+// typeEnum returns the enum of a named basic type (type Status string) resolved by the type checker
+func (parser *Parser) typeEnum(t types.Type) []string {
+	if pointer, ok := t.(*types.Pointer); ok {
+		t = pointer.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return nil
+	}
+	if _, ok := named.Underlying().(*types.Basic); !ok || isMarshaler(named) {
+		// Marshalers do not encode the constants as they are
+		return nil
+	}
+	return parser.GetTypeEnum(named.Obj().Name(), named.Obj().Pkg().Path())
+}
+
+// typeEnum returns the enum of a named basic type used in an annotation
+func (operation *Operation) typeEnum(typeName string) []string {
+	_, modelPackage, err := operation.parser.FindModelDefinition(typeName, operation.parser.CurrentPackage)
+	if err != nil {
+		return nil
+	}
+	return operation.parser.GetTypeEnum(typeName[strings.LastIndex(typeName, ".")+1:], modelPackage)
+}
+
+func constantString(value constant.Value) string {
+	switch value.Kind() {
+	case constant.String:
+		return constant.StringVal(value)
+	case constant.Float:
+		f, _ := constant.Float64Val(value)
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return value.ExactString()
+}
@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"strings"
+)
+
+// ParseError is a parser failure, located at the declaration or annotation which caused it
+type ParseError struct {
+	Package    string
+	File       string
+	Line       int
+	Annotation string
+	Err        error
+}
+
+func (e *ParseError) Error() string {
+	var location []string
+	if e.File != "" {
+		location = append(location, fmt.Sprintf("%s:%d", e.File, e.Line))
+	}
+	if e.Package != "" {
+		location = append(location, "package "+e.Package)
+	}
+	if e.Annotation != "" {
+		location = append(location, fmt.Sprintf("annotation %q", e.Annotation))
+	}
+	if len(location) == 0 {
+		return e.Err.Error()
+	}
+	return strings.Join(location, ", ") + ": " + e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseErrors are all the failures collected during parsing, if Parser.CollectErrors is set
+type ParseErrors []*ParseError
+
+func (errs ParseErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// newParseError wraps err with its location. Errors which are already located
+// deeper (e.g. at a model field) only get the missing details filled in
+func (parser *Parser) newParseError(pos token.Pos, packageName, annotation string, err error) *ParseError {
+	var parseError *ParseError
+	if !errors.As(err, &parseError) {
+		parseError = &ParseError{Err: err}
+		if list, ok := err.(scanner.ErrorList); ok && len(list) > 0 {
+			parseError.File, parseError.Line, parseError.Err = list[0].Pos.Filename, list[0].Pos.Line, errors.New(list[0].Msg)
+		}
+	}
+
+	if parseError.File == "" && pos.IsValid() {
+		position := parser.FileSet.Position(pos)
+		parseError.File, parseError.Line = position.Filename, position.Line
+	}
+	if parseError.Package == "" {
+		parseError.Package = packageName
+	}
+	if parseError.Annotation == "" {
+		parseError.Annotation = annotation
+	}
+	return parseError
+}
+
+// handleError returns err, or records it and returns nil if the parser collects errors,
+// so the caller skips the failed declaration and goes on
+func (parser *Parser) handleError(err *ParseError) error {
+	if !parser.CollectErrors {
+		return err
+	}
+	parser.Errors = append(parser.Errors, err)
+	return nil
+}
@@ -0,0 +1,100 @@
+package parser_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/yvasiyarov/swagger/parser"
+)
+
+type ErrorsSuite struct {
+	suite.Suite
+	root   string
+	parser *parser.Parser
+}
+
+var brokenSources = map[string]string{
+	"go.mod": "module example.com/broken\n",
+	"api/api.go": `package api
+
+import (
+	_ "example.com/absent"
+)
+
+// @Title getUser
+// @Success 200 {object} Missing
+// @Router /users [get]
+func GetUser() {}
+
+// @Title createOrder
+// @Param order body OtherMissing true "Order"
+// @Router /orders [post]
+func CreateOrder() {}
+`,
+}
+
+func (suite *ErrorsSuite) SetupTest() {
+	var err error
+	suite.root, err = ioutil.TempDir("", "swagger-errors")
+	assert.NoError(suite.T(), err, "Can not create temp dir")
+
+	for name, content := range brokenSources {
+		name = filepath.Join(suite.root, filepath.FromSlash(name))
+		assert.NoError(suite.T(), os.MkdirAll(filepath.Dir(name), 0777), "Can not create dir")
+		assert.NoError(suite.T(), ioutil.WriteFile(name, []byte(content), 0666), "Can not write file")
+	}
+
+	suite.parser, err = parser.NewParser(filepath.Join(suite.root, "api"), "", "^$", "", false)
+	assert.NoError(suite.T(), err, "Unable to complete suite initialization")
+}
+
+func (suite *ErrorsSuite) TearDownTest() {
+	os.RemoveAll(suite.root)
+}
+
+func (suite *ErrorsSuite) TestStopAtFirstError() {
+	err := suite.parser.ParseApi()
+
+	var parseError *parser.ParseError
+	if assert.True(suite.T(), errors.As(err, &parseError), "Parser must return a ParseError") {
+		assert.Equal(suite.T(), "example.com/broken/api", parseError.Package, "Package not set")
+		assert.Equal(suite.T(), "api.go", filepath.Base(parseError.File), "File not set")
+		assert.Equal(suite.T(), 4, parseError.Line, "Line of the import not set")
+		assert.Contains(suite.T(), parseError.Error(), "Can not find package example.com/absent", "Cause not reported")
+	}
+}
+
+func (suite *ErrorsSuite) TestCollectErrors() {
+	suite.parser.CollectErrors = true
+	err := suite.parser.ParseApi()
+
+	var parseErrors parser.ParseErrors
+	if assert.True(suite.T(), errors.As(err, &parseErrors), "Collected errors must be returned as ParseErrors") {
+		assert.Len(suite.T(), parseErrors, 3, "Not all errors collected")
+
+		assert.Equal(suite.T(), "", parseErrors[0].Annotation, "Import error has no annotation")
+		assert.Equal(suite.T(), "@Success 200 {object} Missing", parseErrors[1].Annotation, "Annotation not set")
+		assert.Equal(suite.T(), 8, parseErrors[1].Line, "Line of the annotation not set")
+		assert.Equal(suite.T(), "@Param order body OtherMissing true \"Order\"", parseErrors[2].Annotation, "Annotation not set")
+		assert.Equal(suite.T(), 13, parseErrors[2].Line, "Line of the annotation not set")
+	}
+	assert.Contains(suite.T(), suite.parser.TopLevelApis, "users", "Parsing must go on after errors")
+	assert.Contains(suite.T(), suite.parser.TopLevelApis, "orders", "Parsing must go on after errors")
+}
+
+func (suite *ErrorsSuite) TestInvalidRegexp() {
+	_, err := parser.NewParser("example.com/broken/api", "(", "^$", "", false)
+	assert.Error(suite.T(), err, "Invalid -controllerClass must be reported")
+
+	_, err = parser.NewParser("example.com/broken/api", "", "[", "", false)
+	assert.Error(suite.T(), err, "Invalid -ignore must be reported")
+}
+
+func TestErrorsSuite(t *testing.T) {
+	suite.Run(t, &ErrorsSuite{})
+}
@@ -0,0 +1,200 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// splitTypeArguments splits an instantiated generic type name, Page[model.User] or
+// github.com/foo/bar.Pair[int,string], into the generic type name and its type arguments.
+// Other names are returned unchanged
+func splitTypeArguments(typeName string) (string, []string) {
+	start := strings.Index(typeName, "[")
+	if start <= 0 || strings.HasPrefix(typeName, "map[") || !strings.HasSuffix(typeName, "]") {
+		return typeName, nil
+	}
+
+	var arguments []string
+	depth, argumentStart := 0, start+1
+	for i := start; i < len(typeName); i++ {
+		switch typeName[i] {
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				if i != len(typeName)-1 {
+					return typeName, nil
+				}
+				arguments = append(arguments, strings.TrimSpace(typeName[argumentStart:i]))
+			}
+		case ',':
+			if depth == 1 {
+				arguments = append(arguments, strings.TrimSpace(typeName[argumentStart:i]))
+				argumentStart = i + 1
+			}
+		}
+	}
+	return typeName[:start], arguments
+}
+
+// resolveType returns the type checked type of a type name used in annotations or rendered by TypeString
+func (parser *Parser) resolveType(typeName, currentPackage string) (types.Type, error) {
+	switch {
+	case strings.HasPrefix(typeName, "*"):
+		elem, err := parser.resolveType(typeName[1:], currentPackage)
+		if err != nil {
+			return nil, err
+		}
+		return types.NewPointer(elem), nil
+	case strings.HasPrefix(typeName, "[]"):
+		elem, err := parser.resolveType(typeName[2:], currentPackage)
+		if err != nil {
+			return nil, err
+		}
+		return types.NewSlice(elem), nil
+	case strings.HasPrefix(typeName, "map["):
+		end := closingBracket(typeName)
+		key, err := parser.resolveType(typeName[4:end], currentPackage)
+		if err != nil {
+			return nil, err
+		}
+		value, err := parser.resolveType(typeName[end+1:], currentPackage)
+		if err != nil {
+			return nil, err
+		}
+		return types.NewMap(key, value), nil
+	case typeName == "interface" || typeName == "interface{}":
+		return types.NewInterfaceType(nil, nil), nil
+	case typeName == "Time" || typeName == "time.Time":
+		pkg, err := parser.Import("time")
+		if err != nil {
+			return nil, err
+		}
+		return pkg.Scope().Lookup("Time").Type(), nil
+	}
+
+	if object, ok := types.Universe.Lookup(typeName).(*types.TypeName); ok {
+		return object.Type(), nil
+	}
+	return parser.namedType(typeName, currentPackage)
+}
+
+// namedType returns the type checked model, instantiated with its type arguments if it is generic
+func (parser *Parser) namedType(typeName, currentPackage string) (*types.Named, error) {
+	genericName, arguments := splitTypeArguments(typeName)
+	typeSpec, modelPackage, err := parser.FindModelDefinition(genericName, currentPackage)
+	if err != nil {
+		return nil, err
+	}
+
+	var named *types.Named
+	if parser.TypesInfo(modelPackage) != nil {
+		if pkg := parser.TypesPackages[parser.CheckRealPackagePath(modelPackage)]; pkg != nil {
+			if object, ok := pkg.Scope().Lookup(typeSpec.Name.Name).(*types.TypeName); ok {
+				named, _ = object.Type().(*types.Named)
+			}
+		}
+	}
+	if named == nil {
+		return nil, fmt.Errorf("Can not find type information of %s model in package %s", typeSpec.Name.Name, modelPackage)
+	}
+	if len(arguments) == 0 {
+		return named, nil
+	}
+
+	typeArguments := make([]types.Type, 0, len(arguments))
+	for _, argument := range arguments {
+		typeArgument, err := parser.resolveType(argument, currentPackage)
+		if err != nil {
+			return nil, err
+		}
+		typeArguments = append(typeArguments, typeArgument)
+	}
+	instance, err := types.Instantiate(nil, named, typeArguments, true)
+	if err != nil {
+		return nil, fmt.Errorf("Can not instantiate %s: %v", typeName, err)
+	}
+	return instance.(*types.Named), nil
+}
+
+// instanceFieldTypes maps the fields of a generic struct to their types with the type arguments substituted
+func instanceFieldTypes(structType *ast.StructType, instance *types.Named) map[*ast.Field]types.Type {
+	underlying, ok := instance.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	fieldTypes := make(map[*ast.Field]types.Type)
+	i := 0
+	for _, field := range structType.Fields.List {
+		if i >= underlying.NumFields() {
+			break
+		}
+		fieldTypes[field] = underlying.Field(i).Type()
+		if len(field.Names) > 1 {
+			i += len(field.Names)
+		} else {
+			i++
+		}
+	}
+	return fieldTypes
+}
+
+// instanceName names the model of an instantiated generic type after its type arguments, e.g. Page_User.
+// Type arguments of different packages may have the same name, like Page[a.User] and Page[b.User].
+// The clash is reported and the later instance is named after the packages of its type arguments,
+// e.g. Page_example_com_b_User, so it does not overwrite the model of the other one
+func (parser *Parser) instanceName(modelPackage, name string, instance *types.Named) string {
+	if parser.instanceTypes == nil {
+		parser.instanceTypes = make(map[string]string)
+	}
+	instanceType := instance.String()
+	shortName := typeInstanceName(name, instance, false)
+	other, ok := parser.instanceTypes[modelPackage+"."+shortName]
+	if !ok || other == instanceType {
+		parser.instanceTypes[modelPackage+"."+shortName] = instanceType
+		return shortName
+	}
+
+	qualifiedName := typeInstanceName(name, instance, true)
+	if _, ok := parser.instanceTypes[modelPackage+"."+qualifiedName]; !ok {
+		parser.instanceTypes[modelPackage+"."+qualifiedName] = instanceType
+		parser.warn(token.NoPos, modelPackage, "", fmt.Errorf("Model name %s of %s is already used by %s, it is named %s", shortName, instanceType, other, qualifiedName))
+	}
+	return qualifiedName
+}
+
+// typeInstanceName joins the name of a generic type with the names of its type arguments.
+// If qualified is set, the names of named types are prefixed with their package path
+func typeInstanceName(name string, instance *types.Named, qualified bool) string {
+	parts := []string{name}
+	for i := 0; i < instance.TypeArgs().Len(); i++ {
+		parts = append(parts, typeArgumentName(instance.TypeArgs().At(i), qualified))
+	}
+	return strings.Join(parts, "_")
+}
+
+func typeArgumentName(t types.Type, qualified bool) string {
+	switch t := t.(type) {
+	case *types.Basic:
+		return t.Name()
+	case *types.Pointer:
+		return typeArgumentName(t.Elem(), qualified)
+	case *types.Slice:
+		return "array_" + typeArgumentName(t.Elem(), qualified)
+	case *types.Array:
+		return "array_" + typeArgumentName(t.Elem(), qualified)
+	case *types.Map:
+		return "map_" + typeArgumentName(t.Key(), qualified) + "_" + typeArgumentName(t.Elem(), qualified)
+	case *types.Named:
+		name := t.Obj().Name()
+		if qualified && t.Obj().Pkg() != nil {
+			name = strings.NewReplacer("/", "_", ".", "_").Replace(t.Obj().Pkg().Path()) + "_" + name
+		}
+		return typeInstanceName(name, t, qualified)
+	}
+	return "interface"
+}
@@ -0,0 +1,181 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var pathParamPlaceholder = regexp.MustCompile(`\{([^}/]+)\}`)
+
+// warn records an annotation which was skipped or is inconsistent
+func (parser *Parser) warn(pos token.Pos, packageName, annotation string, err error) {
+	parser.Warnings = append(parser.Warnings, parser.newParseError(pos, packageName, annotation, err))
+}
+
+// checkOperation warns about path params and @Router placeholders which do not match each other,
+// about params which do not fit into a single request body, undeclared security definitions,
+// headers of undeclared responses and nicknames used by more than one operation
+func (parser *Parser) checkOperation(operation *Operation, doc *ast.CommentGroup) {
+	placeholders := make(map[string]bool)
+	for _, match := range pathParamPlaceholder.FindAllStringSubmatch(operation.Path, -1) {
+		placeholders[match[1]] = true
+	}
+
+	pathParams := make(map[string]bool)
+	for _, param := range operation.Parameters {
+		if param.ParamType != "path" {
+			continue
+		}
+		pathParams[param.Name] = true
+		if !placeholders[param.Name] {
+			pos, annotation := findAnnotation(doc, "@param", param.Name)
+			parser.warn(pos, operation.packageName, annotation, fmt.Errorf("Path param %s is missing from @Router %s", param.Name, operation.Path))
+		}
+	}
+
+	missingParams := make([]string, 0)
+	for placeholder := range placeholders {
+		if !pathParams[placeholder] {
+			missingParams = append(missingParams, placeholder)
+		}
+	}
+	sort.Strings(missingParams)
+	for _, placeholder := range missingParams {
+		pos, annotation := findAnnotation(doc, "@router", "")
+		parser.warn(pos, operation.packageName, annotation, fmt.Errorf("@Router placeholder {%s} has no matching path @Param", placeholder))
+	}
+
+	// A request has a single body, which is either the first body param or the form params
+	body := -1
+	for i, param := range operation.Parameters {
+		if param.ParamType == "body" {
+			body = i
+			break
+		}
+	}
+	for i, param := range operation.Parameters {
+		if body < 0 || i == body || (param.ParamType != "body" && param.ParamType != "form") {
+			continue
+		}
+		pos, annotation := findAnnotation(doc, "@param", param.Name)
+		if param.ParamType == "body" {
+			parser.warn(pos, operation.packageName, annotation, fmt.Errorf("Only one body param is allowed, %s is the body already", operation.Parameters[body].Name))
+		} else {
+			parser.warn(pos, operation.packageName, annotation, fmt.Errorf("Form param %s can not be sent with body param %s", param.Name, operation.Parameters[body].Name))
+		}
+	}
+
+	names := make([]string, 0, len(operation.Security))
+	for name := range operation.Security {
+		if _, ok := parser.Listing.Authorizations[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		pos, annotation := findAnnotation(doc, "@security", name)
+		parser.warn(pos, operation.packageName, annotation, fmt.Errorf("Security definition %s is not declared", name))
+	}
+
+	codes := make([]int, 0, len(operation.pendingHeaders))
+	for code := range operation.pendingHeaders {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		for _, header := range operation.pendingHeaders[code] {
+			pos, annotation := findHeaderAnnotation(doc, header.Name)
+			parser.warn(pos, operation.packageName, annotation, fmt.Errorf("@Header %s has no response with code %d", header.Name, code))
+		}
+	}
+
+	codes = codes[:0]
+	for code := range operation.pendingExamples {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		pos, annotation := findAnnotation(doc, "@example", strconv.Itoa(code))
+		if pos == token.NoPos {
+			pos, annotation = findAnnotation(doc, "@example", "")
+		}
+		parser.warn(pos, operation.packageName, annotation, fmt.Errorf("@Example has no response with code %d", code))
+	}
+
+	if !operation.Deprecated {
+		for _, model := range operation.Models {
+			if model.Deprecated {
+				pos, annotation := findTypeAnnotation(doc, model.Id)
+				parser.warn(pos, operation.packageName, annotation, fmt.Errorf("Operation %s %s uses deprecated model %s", operation.HttpMethod, operation.Path, model.Id))
+			}
+		}
+	}
+
+	if operation.Nickname != "" {
+		pos, annotation := findAnnotation(doc, "@title", "")
+		if parser.nicknames == nil {
+			parser.nicknames = make(map[string]string)
+		}
+		if first, ok := parser.nicknames[operation.Nickname]; ok {
+			parser.warn(pos, operation.packageName, annotation, fmt.Errorf("Duplicate nickname %s, already used at %s", operation.Nickname, first))
+		} else {
+			parser.nicknames[operation.Nickname] = parser.Position(pos)
+		}
+	}
+}
+
+// findAnnotation returns the position and text of the first comment with the attribute.
+// If argument is not empty, the first word after the attribute must match it
+func findAnnotation(doc *ast.CommentGroup, attribute, argument string) (token.Pos, string) {
+	if doc == nil {
+		return token.NoPos, ""
+	}
+	for _, comment := range doc.List {
+		annotation := strings.TrimSpace(strings.TrimLeft(comment.Text, "/"))
+		fields := strings.Fields(annotation)
+		if len(fields) == 0 || strings.ToLower(fields[0]) != attribute {
+			continue
+		}
+		if argument == "" || (len(fields) > 1 && fields[1] == argument) {
+			return comment.Pos(), annotation
+		}
+	}
+	return token.NoPos, ""
+}
+
+// findHeaderAnnotation returns the position and text of the @Header comment of the header
+func findHeaderAnnotation(doc *ast.CommentGroup, name string) (token.Pos, string) {
+	if doc == nil {
+		return token.NoPos, ""
+	}
+	for _, comment := range doc.List {
+		annotation := strings.TrimSpace(strings.TrimLeft(comment.Text, "/"))
+		fields := strings.Fields(annotation)
+		if len(fields) > 3 && strings.ToLower(fields[0]) == "@header" && fields[3] == name {
+			return comment.Pos(), annotation
+		}
+	}
+	return token.NoPos, ""
+}
+
+// findTypeAnnotation returns the position and text of the first annotation which mentions the model,
+// models which are only used by other models are reported at @Router
+func findTypeAnnotation(doc *ast.CommentGroup, modelId string) (token.Pos, string) {
+	if doc == nil {
+		return token.NoPos, ""
+	}
+	name := modelId[strings.LastIndex(modelId, ".")+1:]
+	typeName := regexp.MustCompile(`(^|[^\w])` + regexp.QuoteMeta(name) + `([^\w]|$)`)
+	for _, comment := range doc.List {
+		annotation := strings.TrimSpace(strings.TrimLeft(comment.Text, "/"))
+		if strings.HasPrefix(annotation, "@") && typeName.MatchString(annotation) {
+			return comment.Pos(), annotation
+		}
+	}
+	return findAnnotation(doc, "@router", "")
+}
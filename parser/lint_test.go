@@ -0,0 +1,139 @@
+package parser_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/yvasiyarov/swagger/parser"
+)
+
+type LintSuite struct {
+	suite.Suite
+	root   string
+	parser *parser.Parser
+}
+
+const lintSource = `package api
+
+// @SubApi Broken sub api
+// @Title getUser
+// @Param id path int true "User ID"
+// @Router /users/{uid} [get]
+func GetUser() {}
+
+// @Title getUser
+// @Param limit query int "Limit"
+// @Sucess 200 {object} string
+// @Security ApiKeyAuth
+// @Header 200 {int} X-Total-Count "Total"
+// @Router /users [get]
+func ListUsers() {}
+
+// Deprecated: use User instead.
+type Account struct {
+	Id int
+}
+
+// @Title getAccount
+// @Success 200 {object} Account
+// @Router /accounts [get]
+func GetAccount() {}
+
+// GetAccountV1 is the first version of GetAccount.
+//
+// Deprecated: use GetAccount instead.
+// @Title getAccountV1
+// @Success 200 {object} Account
+// @Router /v1/accounts [get]
+func GetAccountV1() {}
+
+// @Title createAccount
+// @Param note form string false "Note"
+// @Param account body string true "Account"
+// @Param owner body string true "Owner"
+// @Router /accounts [post]
+func CreateAccount() {}
+
+// @APIVersion 1.0.0
+// @APITitle Lint
+// @Environment dev http://127.0.0.1:3000/
+// @SecurityDefinitions.apikey ApiKeyAuth
+// @In header
+// @Name Authorization
+// @SecurityDefinitions.oauth2.accessCode OAuth2
+// @AuthorizationUrl https://example.com/oauth/authorize
+// @TokenUrl https://example.com/oauth/token
+// @Scope.write Grants write access
+func main() {}
+`
+
+func (suite *LintSuite) SetupSuite() {
+	var err error
+	suite.root, err = ioutil.TempDir("", "swagger-lint")
+	assert.NoError(suite.T(), err, "Can not create temp dir")
+
+	assert.NoError(suite.T(), os.MkdirAll(filepath.Join(suite.root, "api"), 0777), "Can not create dir")
+	assert.NoError(suite.T(), ioutil.WriteFile(filepath.Join(suite.root, "go.mod"), []byte("module example.com/lint\n"), 0666), "Can not write file")
+	assert.NoError(suite.T(), ioutil.WriteFile(filepath.Join(suite.root, "api", "api.go"), []byte(lintSource), 0666), "Can not write file")
+
+	suite.parser, err = parser.NewParser(filepath.Join(suite.root, "api"), "", "^$", "", false)
+	assert.NoError(suite.T(), err, "Unable to complete suite initialization")
+	assert.NoError(suite.T(), suite.parser.ParseApi(), "Skipped annotations must not stop parsing")
+}
+
+func (suite *LintSuite) TearDownSuite() {
+	os.RemoveAll(suite.root)
+}
+
+func (suite *LintSuite) TestWarnings() {
+	type warning struct {
+		Line    int
+		Message string
+	}
+	warnings := make([]warning, 0, len(suite.parser.Warnings))
+	for _, w := range suite.parser.Warnings {
+		assert.Equal(suite.T(), "api.go", filepath.Base(w.File), "File not set")
+		warnings = append(warnings, warning{w.Line, w.Err.Error()})
+	}
+
+	assert.ElementsMatch(suite.T(), []warning{
+		{3, `Can not parse sub api description "Broken sub api", skipped.`},
+		{5, "Path param id is missing from @Router /users/{uid}"},
+		{6, "@Router placeholder {uid} has no matching path @Param"},
+		{9, "Duplicate nickname getUser, already used at " + filepath.Join(suite.root, "api", "api.go") + ":4"},
+		{10, `Can not parse param comment "limit query int "Limit"", skipped.`},
+		{11, "Unknown annotation @Sucess, skipped."},
+		{12, "Security definition ApiKeyAuth is not declared"},
+		{13, "@Header X-Total-Count has no response with code 200"},
+		{23, "Operation GET /accounts uses deprecated model example.com.lint.api.Account"},
+		{36, "Form param note can not be sent with body param account"},
+		{38, "Only one body param is allowed, account is the body already"},
+	}, warnings, "Annotation problems not reported")
+}
+
+func (suite *LintSuite) TestDeprecated() {
+	deprecated := make(map[string]bool)
+	var account *parser.Model
+	for _, api := range suite.parser.TopLevelApis {
+		for _, subApi := range api.Apis {
+			for _, op := range subApi.Operations {
+				deprecated[op.Nickname] = op.Deprecated
+			}
+		}
+		if model, ok := api.Models["example.com.lint.api.Account"]; ok {
+			account = model
+		}
+	}
+	if assert.NotNil(suite.T(), account, "Model not parsed") {
+		assert.True(suite.T(), account.Deprecated, "Deprecated model not marked")
+	}
+	assert.Equal(suite.T(), map[string]bool{"getUser": false, "getAccount": false, "getAccountV1": true, "createAccount": false}, deprecated, "Deprecated: paragraph not recognized")
+}
+
+func TestLintSuite(t *testing.T) {
+	suite.Run(t, &LintSuite{})
+}
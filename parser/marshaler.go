@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// parseSwaggerTypeAnnotation records the primitive declared on a type by // @SwaggerType string uuid.
+// Such types are documented as the primitive instead of their fields
+func (parser *Parser) parseSwaggerTypeAnnotation(pkgRealPath, packageName string, typeSpec *ast.TypeSpec, doc *ast.CommentGroup) {
+	if typeSpec.Doc != nil {
+		doc = typeSpec.Doc
+	}
+	if doc == nil {
+		return
+	}
+	for _, comment := range doc.List {
+		annotation := strings.TrimSpace(strings.TrimLeft(comment.Text, "/"))
+		fields := strings.Fields(annotation)
+		if len(fields) == 0 || strings.ToLower(fields[0]) != "@swaggertype" {
+			continue
+		}
+		if len(fields) < 2 || len(fields) > 3 {
+			parser.warn(comment.Pos(), packageName, annotation, fmt.Errorf("Can not parse swagger type of %s, skipped.", typeSpec.Name.Name))
+			continue
+		}
+		if swaggerType, _ := SwaggerType(fields[1]); swaggerType == "" {
+			parser.warn(comment.Pos(), packageName, annotation, fmt.Errorf("Unknown swagger type %s of %s, skipped.", fields[1], typeSpec.Name.Name))
+			continue
+		}
+		parser.SwaggerTypes[pkgRealPath][typeSpec.Name.Name] = strings.Join(fields[1:], " ")
+	}
+}
+
+// MarshalerType returns the Swagger type and format of types encoded as a primitive: types with
+// a @SwaggerType annotation and the types of TypesImplementingMarshalInterface, by qualified name
+func (parser *Parser) MarshalerType(typeName string) (string, string, bool) {
+	if packagePath, name := splitQualifiedTypeName(typeName); packagePath != "" {
+		if declared, ok := parser.SwaggerTypes[parser.CheckRealPackagePath(packagePath)][name]; ok {
+			swaggerType, format := splitSwaggerType(declared)
+			return swaggerType, format, true
+		}
+	}
+
+	configured, ok := parser.TypesImplementingMarshalInterface[typeName]
+	if !ok {
+		return "", "", false
+	}
+	swaggerType, format := splitSwaggerType(configured)
+	return swaggerType, format, true
+}
+
+// isPrimitiveType reports whether the named type is documented as a primitive by MarshalerType
+func (parser *Parser) isPrimitiveType(t *types.Named) bool {
+	obj := t.Obj()
+	if obj.Pkg() == nil {
+		return false
+	}
+	_, _, ok := parser.MarshalerType(obj.Pkg().Path() + "." + obj.Name())
+	return ok
+}
+
+// checkMarshaler warns once about types used by a model which implement json.Marshaler or encoding.TextMarshaler,
+// but are neither annotated by @SwaggerType nor configured. Their fields are documented, which may not be what they encode
+func (parser *Parser) checkMarshaler(t types.Type, pos token.Pos, packageName string) {
+	if pointer, ok := t.(*types.Pointer); ok {
+		t = pointer.Elem()
+	}
+	if _, ok := t.(*types.Named); !ok {
+		if collection, ok := t.Underlying().(interface{ Elem() types.Type }); ok {
+			t = collection.Elem()
+		}
+		if pointer, ok := t.(*types.Pointer); ok {
+			t = pointer.Elem()
+		}
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || !isMarshaler(named) {
+		return
+	}
+	typeName := named.Obj().Pkg().Path() + "." + named.Obj().Name()
+	if typeName == "time.Time" || parser.isPrimitiveType(named) || parser.checkedMarshalers[typeName] {
+		return
+	}
+	if parser.checkedMarshalers == nil {
+		parser.checkedMarshalers = make(map[string]bool)
+	}
+	parser.checkedMarshalers[typeName] = true
+	parser.warn(pos, packageName, "", fmt.Errorf("%s is a marshaler without @SwaggerType, its fields are documented", typeName))
+}
+
+// isMarshaler reports whether the type or a pointer to it has a MarshalJSON or MarshalText method
+func isMarshaler(t types.Type) bool {
+	methods := types.NewMethodSet(types.NewPointer(t))
+	for _, name := range []string{"MarshalJSON", "MarshalText"} {
+		selection := methods.Lookup(nil, name)
+		if selection == nil {
+			continue
+		}
+		if signature, ok := selection.Type().(*types.Signature); ok && signature.Params().Len() == 0 && signature.Results().Len() == 2 {
+			return true
+		}
+	}
+	return false
+}
+
+// splitSwaggerType splits "string uuid" into type and format. Go types are translated
+// to Swagger types, "int64" is the same as "integer int64"
+func splitSwaggerType(declared string) (string, string) {
+	fields := strings.Fields(declared)
+	if len(fields) == 0 {
+		return "", ""
+	}
+	swaggerType, format := SwaggerType(fields[0])
+	if swaggerType == "" {
+		swaggerType = fields[0]
+	}
+	if len(fields) > 1 {
+		format = fields[1]
+	}
+	return swaggerType, format
+}
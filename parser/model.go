@@ -3,8 +3,11 @@ package parser
 import (
 	"fmt"
 	"go/ast"
+	"go/token"
+	"go/types"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -12,7 +15,10 @@ type Model struct {
 	Id         string                    `json:"id"`
 	Required   []string                  `json:"required,omitempty"`
 	Properties map[string]*ModelProperty `json:"properties"`
+	Deprecated bool                      `json:"-"`
 	parser     *Parser
+	// Types of the fields of instantiated generic types, with the type arguments substituted
+	fieldTypes map[*ast.Field]types.Type
 }
 
 func NewModel(p *Parser) *Model {
@@ -26,27 +32,23 @@ func (m *Model) ParseModel(modelName string, currentPackage string, knownModelNa
 	knownModelNames[modelName] = true
 	//log.Printf("Before parse model |%s|, package: |%s|\n", modelName, currentPackage)
 
-	astTypeSpec, modelPackage := m.parser.FindModelDefinition(modelName, currentPackage)
-
-	modelNameParts := strings.Split(modelName, ".")
-	m.Id = strings.Join(append(strings.Split(modelPackage, "/"), modelNameParts[len(modelNameParts)-1]), ".")
+	astTypeSpec, modelPackage, err := m.findDefinition(modelName, currentPackage)
+	if err != nil {
+		return m.parser.newParseError(token.NoPos, currentPackage, "", err), nil
+	}
+	m.Deprecated = m.parser.DeprecatedTypes[m.parser.CheckRealPackagePath(modelPackage)][astTypeSpec.Name.String()]
 
 	var innerModelList []*Model
 	if astTypeDef, ok := astTypeSpec.Type.(*ast.Ident); ok {
 		typeDefTranslations[astTypeSpec.Name.String()] = astTypeDef.Name
 	} else if astStructType, ok := astTypeSpec.Type.(*ast.StructType); ok {
-		m.ParseFieldList(astStructType.Fields.List, modelPackage)
+		if err := m.ParseFieldList(astStructType.Fields.List, modelPackage); err != nil {
+			return err, nil
+		}
 		usedTypes := make(map[string]bool)
 
 		for _, property := range m.Properties {
-			typeName := property.Type
-			if typeName == "array" {
-				if property.Items.Type != "" {
-					typeName = property.Items.Type
-				} else {
-					typeName = property.Items.Ref
-				}
-			}
+			typeName := property.ValueType()
 			if translation, ok := typeDefTranslations[typeName]; ok {
 				typeName = translation
 			}
@@ -54,6 +56,13 @@ func (m *Model) ParseModel(modelName string, currentPackage string, knownModelNa
 				continue
 			}
 			if _, exists := knownModelNames[typeName]; exists {
+				// Parsed already, only the reference needs the model id
+				knownModel := NewModel(m.parser)
+				if _, _, err := knownModel.findDefinition(typeName, modelPackage); err == nil {
+					for _, property := range m.Properties {
+						property.ReplaceValueType(typeName, knownModel.Id)
+					}
+				}
 				continue
 			}
 
@@ -63,22 +72,20 @@ func (m *Model) ParseModel(modelName string, currentPackage string, knownModelNa
 		//log.Printf("Before parse inner model list: %#v\n (%s)", usedTypes, modelName)
 		innerModelList = make([]*Model, 0, len(usedTypes))
 
-		for typeName, _ := range usedTypes {
+		// Sorted, so the same model wins when inner models share an id
+		typeNames := make([]string, 0, len(usedTypes))
+		for typeName := range usedTypes {
+			typeNames = append(typeNames, typeName)
+		}
+		sort.Strings(typeNames)
+		for _, typeName := range typeNames {
 			typeModel := NewModel(m.parser)
 			if err, typeInnerModels := typeModel.ParseModel(typeName, modelPackage, knownModelNames); err != nil {
 				//log.Printf("Parse Inner Model error %#v \n", err)
 				return err, nil
 			} else {
 				for _, property := range m.Properties {
-					if property.Type == "array" {
-						if property.Items.Ref == typeName {
-							property.Items.Ref = typeModel.Id
-						}
-					} else {
-						if property.Type == typeName {
-							property.Type = typeModel.Id
-						}
-					}
+					property.ReplaceValueType(typeName, typeModel.Id)
 				}
 				//log.Printf("Inner model %v parsed, parsing %s \n", typeName, modelName)
 				if typeModel != nil {
@@ -99,42 +106,106 @@ func (m *Model) ParseModel(modelName string, currentPackage string, knownModelNa
 	return nil, innerModelList
 }
 
-func (m *Model) ParseFieldList(fieldList []*ast.Field, modelPackage string) {
+// findDefinition finds the type declaration of the model and sets the model id
+func (m *Model) findDefinition(modelName string, currentPackage string) (*ast.TypeSpec, string, error) {
+	genericName, typeArguments := splitTypeArguments(modelName)
+	astTypeSpec, modelPackage, err := m.parser.FindModelDefinition(genericName, currentPackage)
+	if err != nil {
+		return nil, "", err
+	}
+
+	modelNameParts := strings.Split(genericName, ".")
+	name := modelNameParts[len(modelNameParts)-1]
+	if len(typeArguments) > 0 {
+		// Every instantiation of a generic type is a model of its own
+		instance, err := m.parser.namedType(modelName, currentPackage)
+		if err != nil {
+			return nil, "", err
+		}
+		if astStructType, ok := astTypeSpec.Type.(*ast.StructType); ok {
+			m.fieldTypes = instanceFieldTypes(astStructType, instance)
+		}
+		name = m.parser.instanceName(modelPackage, name, instance)
+	}
+	m.Id = strings.Join(append(strings.Split(modelPackage, "/"), name), ".")
+	return astTypeSpec, modelPackage, nil
+}
+
+func (m *Model) ParseFieldList(fieldList []*ast.Field, modelPackage string) error {
 	if fieldList == nil {
-		return
+		return nil
 	}
 	//log.Printf("ParseFieldList\n")
 
 	m.Properties = make(map[string]*ModelProperty)
 	for _, field := range fieldList {
-		m.ParseModelProperty(field, modelPackage)
+		if err := m.ParseModelProperty(field, modelPackage); err != nil {
+			return m.parser.newParseError(field.Pos(), modelPackage, "", err)
+		}
 	}
+	return nil
 }
 
-func (m *Model) ParseModelProperty(field *ast.Field, modelPackage string) {
+func (m *Model) ParseModelProperty(field *ast.Field, modelPackage string) error {
 	var name string
 	var innerModel *Model
 
 	property := NewModelProperty()
 
-	typeAsString := property.GetTypeAsString(field.Type)
-	//log.Printf("Get type as string %s \n", typeAsString)
+	var typeAsString string
+	fieldType := m.fieldTypes[field]
+	if fieldType == nil {
+		fieldType = m.parser.TypeOfExpr(field.Type, modelPackage)
+	}
+	if fieldType != nil {
+		// Type checked package: the field type is resolved with full import paths
+		if typeAsString = m.parser.TypeString(fieldType); typeAsString == "" {
+			log.Warnf("%s: can not resolve type %v of field in package %s, documenting it as interface",
+				m.parser.Position(field.Type.Pos()), types.ExprString(field.Type), modelPackage)
+			typeAsString = "interface"
+		}
+	} else {
+		typeAsString = property.GetTypeAsString(field.Type)
+		//log.Printf("Get type as string %s \n", typeAsString)
 
-	// Sometimes reflection reports an object as "&{foo Bar}" rather than just "foo.Bar"
-	// The next 2 lines of code normalize them to foo.Bar
-	reInternalRepresentation := regexp.MustCompile("&\\{(\\w*) (\\w*)\\}")
-	typeAsString = string(reInternalRepresentation.ReplaceAll([]byte(typeAsString), []byte("$1.$2")))
+		// Sometimes reflection reports an object as "&{foo Bar}" rather than just "foo.Bar"
+		// The next 2 lines of code normalize them to foo.Bar
+		reInternalRepresentation := regexp.MustCompile("&\\{(\\w*) (\\w*)\\}")
+		typeAsString = string(reInternalRepresentation.ReplaceAll([]byte(typeAsString), []byte("$1.$2")))
+	}
 
-	if strings.HasPrefix(typeAsString, "[]") {
-		property.Type = "array"
-		property.SetItemType(typeAsString[2:])
-	} else if typeAsString == "time.Time" {
-		property.Type = "Time"
-	} else {
-		property.Type = typeAsString
+	property.SetType(typeAsString)
+	if swaggerType, format, ok := m.parser.MarshalerType(property.ValueType()); ok {
+		property.SetValueType(swaggerType, format)
+	} else if fieldType != nil {
+		m.parser.checkMarshaler(fieldType, field.Type.Pos(), modelPackage)
 	}
 
-	if len(field.Names) == 0 {
+	// Constants of named basic types are the allowed values
+	if fieldType != nil && property.Type == "array" {
+		if pointer, ok := fieldType.(*types.Pointer); ok {
+			fieldType = pointer.Elem()
+		}
+		if collection, ok := fieldType.Underlying().(interface{ Elem() types.Type }); ok {
+			property.Items.Enum = m.parser.typeEnum(collection.Elem())
+		}
+	} else if fieldType != nil {
+		property.Enum = m.parser.typeEnum(fieldType)
+	}
+
+	if len(field.Names) == 0 && !IsBasicType(typeAsString) && !strings.HasPrefix(typeAsString, "[]") && !strings.HasPrefix(typeAsString, "map[") {
+		// Embedded type, resolved by the type checker to its qualified name
+		if strings.Contains(typeAsString, "/") {
+			innerModel = NewModel(m.parser)
+			if err, _ := innerModel.ParseModel(typeAsString, modelPackage, map[string]bool{}); err != nil {
+				return err
+			}
+
+			for innerFieldName, innerField := range innerModel.Properties {
+				m.Properties[innerFieldName] = innerField
+			}
+			return nil
+		}
 
 		if astSelectorExpr, ok := field.Type.(*ast.SelectorExpr); ok {
 			packageName := modelPackage
@@ -150,20 +221,26 @@ func (m *Model) ParseModelProperty(field *ast.Field, modelPackage string) {
 				name = astIdent.Name
 			}
 		} else {
-			log.Fatalf("Something goes wrong: %#v", field.Type)
+			return fmt.Errorf("Can not parse embedded field of type %s", types.ExprString(field.Type))
 		}
 		innerModel = NewModel(m.parser)
 		//log.Printf("Try to parse embeded type %s \n", name)
 		//log.Fatalf("DEBUG: field: %#v\n, selector.X: %#v\n selector.Sel: %#v\n", field, astSelectorExpr.X, astSelectorExpr.Sel)
 		knownModelNames := map[string]bool{}
-		innerModel.ParseModel(name, modelPackage, knownModelNames)
+		if err, _ := innerModel.ParseModel(name, modelPackage, knownModelNames); err != nil {
+			return err
+		}
 
 		for innerFieldName, innerField := range innerModel.Properties {
 			m.Properties[innerFieldName] = innerField
 		}
 
 		//log.Fatalf("Here %#v\n", field.Type)
-		return
+		return nil
+	} else if len(field.Names) == 0 {
+		// Embedded named basic or collection type, encoded as a field named after the type
+		name = strings.TrimPrefix(types.ExprString(field.Type), "*")
+		name = name[strings.LastIndex(name, ".")+1:]
 	} else {
 		name = field.Names[0].Name
 	}
@@ -192,7 +269,13 @@ func (m *Model) ParseModelProperty(field *ast.Field, modelPackage string) {
 			}
 			// We will not document at all any fields with a json tag of "-"
 			if v == "-" {
-				return
+				return nil
+			}
+		}
+		// go-playground/validator rules, gin uses the same rules in binding tags
+		for _, tagName := range []string{"binding", "validate"} {
+			if validateTag := structTag.Get(tagName); validateTag != "" && property.ParseValidateTag(validateTag) {
+				isRequired = true
 			}
 		}
 		if required := structTag.Get("required"); required != "" || isRequired {
@@ -201,8 +284,13 @@ func (m *Model) ParseModelProperty(field *ast.Field, modelPackage string) {
 		if desc := structTag.Get("description"); desc != "" {
 			property.Description = desc
 		}
+		if example := structTag.Get("example"); example != "" {
+			property.Example = example
+		}
 	}
+	property.Deprecated = isDeprecated(field.Doc) || isDeprecated(field.Comment)
 	m.Properties[name] = property
+	return nil
 }
 
 type ModelProperty struct {
@@ -210,10 +298,29 @@ type ModelProperty struct {
 	Description string             `json:"description"`
 	Items       ModelPropertyItems `json:"items,omitempty"`
 	Format      string             `json:"format"`
+	Enum        []string           `json:"enum,omitempty"`
+	Example     string             `json:"example,omitempty"`
+	Deprecated  bool               `json:"deprecated,omitempty"`
+
+	// Constraints from validate and binding tags
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum bool     `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum bool     `json:"exclusiveMaximum,omitempty"`
+	MinLength        *int64   `json:"minLength,omitempty"`
+	MaxLength        *int64   `json:"maxLength,omitempty"`
+	MinItems         *int64   `json:"minItems,omitempty"`
+	MaxItems         *int64   `json:"maxItems,omitempty"`
+	Pattern          string   `json:"pattern,omitempty"`
+
+	// Value of maps, which are objects with arbitrary keys
+	AdditionalProperties *ModelProperty `json:"additionalProperties,omitempty"`
 }
 type ModelPropertyItems struct {
-	Ref  string `json:"$ref,omitempty"`
-	Type string `json:"type,omitempty"`
+	Ref    string   `json:"$ref,omitempty"`
+	Type   string   `json:"type,omitempty"`
+	Format string   `json:"format,omitempty"`
+	Enum   []string `json:"enum,omitempty"`
 }
 
 func NewModelProperty() *ModelProperty {
@@ -244,10 +351,42 @@ var basicTypes = map[string]bool{
 	"error":      true,
 	"Time":       true,
 	"file":       true,
+	// Swagger types, basic types are translated to them
+	"integer": true,
+	"number":  true,
+	"boolean": true,
 }
 
 var typeDefTranslations = map[string]string{}
 
+// SwaggerType returns the Swagger type and format of a basic Go type, or an empty type
+// if there is no Swagger equivalent (models, interfaces). Swagger types are returned unchanged
+func SwaggerType(typeName string) (string, string) {
+	switch typeName {
+	case "bool", "boolean":
+		return "boolean", ""
+	case "int", "uint", "uintptr", "integer":
+		return "integer", ""
+	case "int8", "int16", "int32", "uint8", "uint16", "byte", "rune":
+		return "integer", "int32"
+	case "int64", "uint32", "uint64":
+		return "integer", "int64"
+	case "float32":
+		return "number", "float"
+	case "float64":
+		return "number", "double"
+	case "number":
+		return "number", ""
+	case "string", "error":
+		return "string", ""
+	case "Time", "time.Time":
+		return "string", "date-time"
+	case "file":
+		return "file", ""
+	}
+	return "", ""
+}
+
 func IsBasicType(typeName string) bool {
 	_, ok := basicTypes[typeName]
 	return ok || strings.Contains(typeName, "interface")
@@ -255,12 +394,89 @@ func IsBasicType(typeName string) bool {
 
 func (p *ModelProperty) SetItemType(itemType string) {
 	p.Items = ModelPropertyItems{}
-	if IsBasicType(itemType) {
+	if swaggerType, format := SwaggerType(itemType); swaggerType != "" {
+		p.Items.Type, p.Items.Format = swaggerType, format
+	} else if IsBasicType(itemType) {
 		p.Items.Type = itemType
 	} else {
 		p.Items.Ref = itemType
 	}
 }
+
+// SetType sets the type of the property from its string representation: []Item for arrays,
+// map[string]Value for maps, which become objects with additional properties of the value type
+func (p *ModelProperty) SetType(typeAsString string) {
+	if strings.HasPrefix(typeAsString, "map[") {
+		p.Type = "object"
+		p.AdditionalProperties = NewModelProperty()
+		// Keys are strings in JSON, only the value type matters
+		p.AdditionalProperties.SetType(typeAsString[closingBracket(typeAsString)+1:])
+	} else if typeAsString == "[]byte" || typeAsString == "[]uint8" {
+		// Encoded as base64 string
+		p.Type, p.Format = "string", "byte"
+	} else if strings.HasPrefix(typeAsString, "[]") {
+		p.Type = "array"
+		p.SetItemType(typeAsString[2:])
+	} else if swaggerType, format := SwaggerType(typeAsString); swaggerType != "" {
+		p.Type, p.Format = swaggerType, format
+	} else {
+		p.Type = typeAsString
+	}
+}
+
+// ValueType returns the type of the values of arrays and maps, or the type of the property
+func (p *ModelProperty) ValueType() string {
+	if p.AdditionalProperties != nil {
+		return p.AdditionalProperties.ValueType()
+	}
+	if p.Type == "array" {
+		if p.Items.Type != "" {
+			return p.Items.Type
+		}
+		return p.Items.Ref
+	}
+	return p.Type
+}
+
+// ReplaceValueType replaces the value type returned by ValueType, once it is resolved to a model
+func (p *ModelProperty) ReplaceValueType(typeName, modelId string) {
+	if p.AdditionalProperties != nil {
+		p.AdditionalProperties.ReplaceValueType(typeName, modelId)
+	} else if p.Type == "array" {
+		if p.Items.Ref == typeName {
+			p.Items.Ref = modelId
+		}
+	} else if p.Type == typeName {
+		p.Type = modelId
+	}
+}
+
+// SetValueType sets the type of the values of arrays and maps, or the type of the property
+func (p *ModelProperty) SetValueType(swaggerType, format string) {
+	if p.AdditionalProperties != nil {
+		p.AdditionalProperties.SetValueType(swaggerType, format)
+	} else if p.Type == "array" {
+		p.Items = ModelPropertyItems{Type: swaggerType, Format: format}
+	} else {
+		p.Type, p.Format = swaggerType, format
+	}
+}
+
+// closingBracket returns the index of the bracket closing the first one, map[K]V keys can contain brackets too
+func closingBracket(typeAsString string) int {
+	depth := 0
+	for i, c := range typeAsString {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(typeAsString) - 1
+}
 func (p *ModelProperty) GetTypeAsString(fieldType interface{}) string {
 	var realType string
 	if astArrayType, ok := fieldType.(*ast.ArrayType); ok {
@@ -268,9 +484,17 @@ func (p *ModelProperty) GetTypeAsString(fieldType interface{}) string {
 		realType = fmt.Sprintf("[]%v", p.GetTypeAsString(astArrayType.Elt))
 	} else if astMapType, ok := fieldType.(*ast.MapType); ok {
 		//		log.Printf("arrayType: %#v\n", astArrayType)
-		realType = fmt.Sprintf("[]%v", p.GetTypeAsString(astMapType.Value))
+		realType = fmt.Sprintf("map[%v]%v", p.GetTypeAsString(astMapType.Key), p.GetTypeAsString(astMapType.Value))
 	} else if _, ok := fieldType.(*ast.InterfaceType); ok {
 		realType = "interface"
+	} else if astIndexExpr, ok := fieldType.(*ast.IndexExpr); ok {
+		realType = fmt.Sprintf("%v[%v]", p.GetTypeAsString(astIndexExpr.X), p.GetTypeAsString(astIndexExpr.Index))
+	} else if astIndexListExpr, ok := fieldType.(*ast.IndexListExpr); ok {
+		arguments := make([]string, 0, len(astIndexListExpr.Indices))
+		for _, index := range astIndexListExpr.Indices {
+			arguments = append(arguments, p.GetTypeAsString(index))
+		}
+		realType = fmt.Sprintf("%v[%v]", p.GetTypeAsString(astIndexListExpr.X), strings.Join(arguments, ","))
 	} else {
 		if astStarExpr, ok := fieldType.(*ast.StarExpr); ok {
 			realType = fmt.Sprint(astStarExpr.X)
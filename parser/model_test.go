@@ -27,7 +27,8 @@ func (suite *ModelSuite) SetupSuite() {
 		initialisedParser, err = parser.NewParser(apiPackages, "", "^$", "", false)
 		assert.NoError(suite.T(), err, "Unable to complete suite initialization")
 
-		initialisedParser.ParseTypeDefinitions(ExamplePackageName)
+		err = initialisedParser.ParseTypeDefinitions(ExamplePackageName)
+		assert.NoError(suite.T(), err, "Can not parse type definitions")
 	}
 	suite.parser = initialisedParser
 	suite.knownModelNames = make(map[string]bool)
@@ -100,8 +101,9 @@ func (suite *ModelSuite) TestSimpleStructureWithAnnotations() {
 	assert.Len(suite.T(), m.Required, 1, "Can not parse SimpleStructureWithAnnotations definition(%#v)", m.Properties)
 	assert.Len(suite.T(), m.Properties, 2, "Can not parse SimpleStructureWithAnnotations definition")
 
-	assert.Equal(suite.T(), m.Properties["id"].Type, "int", "Can not parse SimpleStructureWithAnnotations definition")
+	assert.Equal(suite.T(), m.Properties["id"].Type, "integer", "Can not parse SimpleStructureWithAnnotations definition")
 	assert.Equal(suite.T(), m.Properties["Name"].Type, "string", "Can not parse SimpleStructureWithAnnotations definition")
+	assert.Equal(suite.T(), "42", m.Properties["id"].Example, "Example tag not parsed")
 }
 
 func (suite *ModelSuite) TestStructureWithSlice() {
@@ -114,9 +116,9 @@ func (suite *ModelSuite) TestStructureWithSlice() {
 	assert.Len(suite.T(), m.Required, 0, "Can not parse StructureWithSlice definition(%#v)", m.Properties)
 	assert.Len(suite.T(), m.Properties, 2, "Can not parse StructureWithSlice definition")
 
-	assert.Equal(suite.T(), m.Properties["Id"].Type, "int", "Can not parse StructureWithSlice definition")
-	assert.Equal(suite.T(), m.Properties["Name"].Type, "array", "Can not parse StructureWithSlice definition")
-	assert.Equal(suite.T(), m.Properties["Name"].Items.Type, "byte", "Can not parse StructureWithSlice definition")
+	assert.Equal(suite.T(), m.Properties["Id"].Type, "integer", "Can not parse StructureWithSlice definition")
+	assert.Equal(suite.T(), m.Properties["Name"].Type, "string", "Can not parse StructureWithSlice definition")
+	assert.Equal(suite.T(), m.Properties["Name"].Format, "byte", "Can not parse StructureWithSlice definition")
 }
 
 func (suite *ModelSuite) TestStructureWithEmbededStructure() {
@@ -130,9 +132,9 @@ func (suite *ModelSuite) TestStructureWithEmbededStructure() {
 	assert.Len(suite.T(), m.Required, 0, "Can not parse StructureWithEmbededStructure definition(%#v)", m.Properties)
 	assert.Len(suite.T(), m.Properties, 2, "Can not parse StructureWithEmbededStructure definition")
 
-	assert.Equal(suite.T(), m.Properties["Id"].Type, "int", "Can not parse StructureWithEmbededStructure definition")
-	assert.Equal(suite.T(), m.Properties["Name"].Type, "array", "Can not parse StructureWithEmbededStructure definition")
-	assert.Equal(suite.T(), m.Properties["Name"].Items.Type, "byte", "Can not parse StructureWithEmbededStructure definition")
+	assert.Equal(suite.T(), m.Properties["Id"].Type, "integer", "Can not parse StructureWithEmbededStructure definition")
+	assert.Equal(suite.T(), m.Properties["Name"].Type, "string", "Can not parse StructureWithEmbededStructure definition")
+	assert.Equal(suite.T(), m.Properties["Name"].Format, "byte", "Can not parse StructureWithEmbededStructure definition")
 }
 
 func (suite *ModelSuite) TestStructureWithEmbededPointer() {
@@ -146,9 +148,75 @@ func (suite *ModelSuite) TestStructureWithEmbededPointer() {
 	assert.Len(suite.T(), m.Required, 0, "Can not parse StructureWithEmbededPointer definition(%#v)", m.Properties)
 	assert.Len(suite.T(), m.Properties, 2, "Can not parse StructureWithEmbededPointer definition")
 
-	assert.Equal(suite.T(), m.Properties["Id"].Type, "int", "Can not parse StructureWithEmbededPointer definition")
-	assert.Equal(suite.T(), m.Properties["Name"].Type, "array", "Can not parse StructureWithEmbededPointer definition")
-	assert.Equal(suite.T(), m.Properties["Name"].Items.Type, "byte", "Can not parse StructureWithEmbededPointer definition")
+	assert.Equal(suite.T(), m.Properties["Id"].Type, "integer", "Can not parse StructureWithEmbededPointer definition")
+	assert.Equal(suite.T(), m.Properties["Name"].Type, "string", "Can not parse StructureWithEmbededPointer definition")
+	assert.Equal(suite.T(), m.Properties["Name"].Format, "byte", "Can not parse StructureWithEmbededPointer definition")
+}
+
+func (suite *ModelSuite) TestStructureWithMarshalers() {
+	m := parser.NewModel(suite.parser)
+	err, _ := m.ParseModel("StructureWithMarshalers", ExamplePackageName, suite.knownModelNames)
+	assert.Nil(suite.T(), err, "Can not parse StructureWithMarshalers definition")
+
+	assert.Equal(suite.T(), "string", m.Properties["Id"].Type, "Type of @SwaggerType annotation not used")
+	assert.Equal(suite.T(), "uuid", m.Properties["Id"].Format, "Format of @SwaggerType annotation not used")
+	assert.Equal(suite.T(), "github.com.yvasiyarov.swagger.example.Money", m.Properties["Price"].Type, "json.Marshaler without @SwaggerType must be a model")
+	assert.Equal(suite.T(), "array", m.Properties["Related"].Type, "Can not parse StructureWithMarshalers definition")
+	assert.Equal(suite.T(), "uuid", m.Properties["Related"].Items.Format, "Items of @SwaggerType annotation not used")
+	assert.Equal(suite.T(), "boolean", m.Properties["Deleted"].Type, "Configured marshaler not used")
+
+	messages := make([]string, 0, len(suite.parser.Warnings))
+	for _, warning := range suite.parser.Warnings {
+		messages = append(messages, warning.Err.Error())
+	}
+	assert.Contains(suite.T(), messages, "github.com/yvasiyarov/swagger/example.Money is a marshaler without @SwaggerType, its fields are documented", "Marshaler without @SwaggerType not reported")
+}
+
+func (suite *ModelSuite) TestConfiguredMarshalers() {
+	defer delete(suite.parser.TypesImplementingMarshalInterface, "Money")
+	suite.parser.TypesImplementingMarshalInterface["Money"] = "string"
+	m := parser.NewModel(suite.parser)
+	err, _ := m.ParseModel("StructureWithMarshalers", ExamplePackageName, suite.knownModelNames)
+	assert.Nil(suite.T(), err, "Can not parse StructureWithMarshalers definition")
+	assert.NotEqual(suite.T(), "string", m.Properties["Price"].Type, "Configured marshalers must match the qualified name")
+
+	defer delete(suite.parser.TypesImplementingMarshalInterface, "github.com/yvasiyarov/swagger/example.Money")
+	suite.parser.TypesImplementingMarshalInterface["github.com/yvasiyarov/swagger/example.Money"] = "string decimal"
+	m = parser.NewModel(suite.parser)
+	err, _ = m.ParseModel("StructureWithMarshalers", ExamplePackageName, suite.knownModelNames)
+	assert.Nil(suite.T(), err, "Can not parse StructureWithMarshalers definition")
+	assert.Equal(suite.T(), "string", m.Properties["Price"].Type, "Configured marshaler not used")
+	assert.Equal(suite.T(), "decimal", m.Properties["Price"].Format, "Format of configured marshaler not used")
+}
+
+func (suite *ModelSuite) TestStructureWithValidation() {
+	m := parser.NewModel(suite.parser)
+	err, _ := m.ParseModel("StructureWithValidation", ExamplePackageName, suite.knownModelNames)
+	assert.Nil(suite.T(), err, "Can not parse StructureWithValidation definition")
+
+	assert.Equal(suite.T(), []string{"name", "email"}, m.Required, "Required rules not parsed")
+
+	name := m.Properties["name"]
+	assert.Equal(suite.T(), int64(1), *name.MinLength, "min rule of string not parsed")
+	assert.Equal(suite.T(), int64(64), *name.MaxLength, "max rule of string not parsed")
+	assert.Equal(suite.T(), "^[a-zA-Z0-9]+$", name.Pattern, "Pattern rule not parsed")
+	assert.Equal(suite.T(), "email", m.Properties["email"].Format, "Format rule not parsed")
+
+	age := m.Properties["age"]
+	assert.Equal(suite.T(), float64(18), *age.Minimum, "gte rule of number not parsed")
+	assert.False(suite.T(), age.ExclusiveMinimum, "gte rule is not exclusive")
+	assert.Equal(suite.T(), float64(130), *age.Maximum, "lt rule of number not parsed")
+	assert.True(suite.T(), age.ExclusiveMaximum, "lt rule is exclusive")
+
+	assert.Equal(suite.T(), []string{"admin", "power user", "guest"}, m.Properties["role"].Enum, "oneof rule not parsed")
+
+	tags := m.Properties["tags"]
+	assert.Equal(suite.T(), int64(1), *tags.MinItems, "min rule of array not parsed")
+	assert.Nil(suite.T(), tags.MaxItems, "Rules after dive apply to the items")
+
+	note := m.Properties["note"]
+	assert.Nil(suite.T(), note.MinLength, "Alternative rules can not be constraints")
+	assert.Nil(suite.T(), note.MaxLength, "Alternative rules can not be constraints")
 }
 
 //TODO:
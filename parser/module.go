@@ -0,0 +1,330 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GoModule is the subset of a go.mod (and go.sum) file needed to find the sources of imported packages
+type GoModule struct {
+	Path     string                   // module path from the "module" directive
+	Dir      string                   // absolute directory containing go.mod
+	Requires map[string]string        // module path => version
+	Replaces map[string]ModuleReplace // module path => replacement
+	CacheDir string                   // module cache, usually $GOPATH/pkg/mod
+}
+
+type ModuleReplace struct {
+	Path    string // module path or a local directory (starts with ./, ../ or /)
+	Version string
+}
+
+// FindGoModule looks for go.mod in dir and its parents. It returns nil if dir is not inside a module
+func FindGoModule(dir string, gopath string) (*GoModule, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		goModFile := filepath.Join(dir, "go.mod")
+		if _, err := os.Stat(goModFile); err == nil {
+			return ParseGoModule(goModFile, gopath)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// ParseGoModule reads the module path, require and replace directives of a go.mod file.
+// Versions of modules which are not listed in go.mod (indirect dependencies of old modules) are taken from go.sum,
+// which lists every version of the module graph: the highest one is used, as the build would select it
+func ParseGoModule(goModFile string, gopath string) (*GoModule, error) {
+	dir, err := filepath.Abs(filepath.Dir(goModFile))
+	if err != nil {
+		return nil, err
+	}
+
+	module := &GoModule{
+		Dir:      dir,
+		Requires: make(map[string]string),
+		Replaces: make(map[string]ModuleReplace),
+		CacheDir: os.Getenv("GOMODCACHE"),
+	}
+	if module.CacheDir == "" {
+		module.CacheDir = filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+	}
+
+	if err := module.readGoSum(filepath.Join(dir, "go.sum")); err != nil {
+		return nil, err
+	}
+
+	fd, err := os.Open(goModFile)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	block := ""
+	scanner := bufio.NewScanner(fd)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if idx := strings.Index(line, "//"); idx != -1 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		directive := block
+		switch {
+		case block != "" && fields[0] == ")":
+			block = ""
+			continue
+		case block == "" && len(fields) == 2 && fields[1] == "(":
+			block = fields[0]
+			continue
+		case block == "":
+			directive, fields = fields[0], fields[1:]
+		}
+
+		switch directive {
+		case "module":
+			if len(fields) != 1 {
+				return nil, fmt.Errorf("%s:%d: invalid module directive", goModFile, lineNumber)
+			}
+			module.Path = unquoteModulePath(fields[0])
+		case "require":
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("%s:%d: invalid require directive", goModFile, lineNumber)
+			}
+			module.Requires[unquoteModulePath(fields[0])] = fields[1]
+		case "replace":
+			// old [version] => new [version]
+			arrow := -1
+			for i, field := range fields {
+				if field == "=>" {
+					arrow = i
+				}
+			}
+			if arrow < 1 || arrow == len(fields)-1 {
+				return nil, fmt.Errorf("%s:%d: invalid replace directive", goModFile, lineNumber)
+			}
+			replace := ModuleReplace{Path: unquoteModulePath(fields[arrow+1])}
+			if len(fields) > arrow+2 {
+				replace.Version = fields[arrow+2]
+			}
+			module.Replaces[unquoteModulePath(fields[0])] = replace
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if module.Path == "" {
+		return nil, fmt.Errorf("%s: module directive is missing", goModFile)
+	}
+
+	return module, nil
+}
+
+func (module *GoModule) readGoSum(goSumFile string) error {
+	fd, err := os.Open(goSumFile)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		// path version[/go.mod] hash
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		// go.sum is sorted as text, v1.10.0 comes before v1.9.0
+		if version, ok := module.Requires[fields[0]]; !ok || compareVersions(fields[1], version) > 0 {
+			module.Requires[fields[0]] = fields[1]
+		}
+	}
+	return scanner.Err()
+}
+
+// compareVersions compares two semantic versions like v1.2.3-pre+build, ignoring build metadata.
+// Pseudo-versions are pre-releases, so they are ordered too
+func compareVersions(a, b string) int {
+	a, b = strings.TrimPrefix(a, "v"), strings.TrimPrefix(b, "v")
+	if i := strings.Index(a, "+"); i >= 0 {
+		a = a[:i]
+	}
+	if i := strings.Index(b, "+"); i >= 0 {
+		b = b[:i]
+	}
+	aCore, aPre, aHasPre := strings.Cut(a, "-")
+	bCore, bPre, bHasPre := strings.Cut(b, "-")
+
+	aParts, bParts := strings.Split(aCore, "."), strings.Split(bCore, ".")
+	for i := 0; i < 3; i++ {
+		var aPart, bPart string
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+		if c := compareIdentifiers(aPart, bPart); c != 0 {
+			return c
+		}
+	}
+
+	// A release is higher than its pre-releases
+	switch {
+	case !aHasPre && !bHasPre:
+		return 0
+	case !aHasPre:
+		return 1
+	case !bHasPre:
+		return -1
+	}
+	aIdentifiers, bIdentifiers := strings.Split(aPre, "."), strings.Split(bPre, ".")
+	for i := 0; i < len(aIdentifiers) && i < len(bIdentifiers); i++ {
+		if c := compareIdentifiers(aIdentifiers[i], bIdentifiers[i]); c != 0 {
+			return c
+		}
+	}
+	return len(aIdentifiers) - len(bIdentifiers)
+}
+
+// compareIdentifiers compares numeric identifiers as numbers, and lower than alphanumeric ones
+func compareIdentifiers(a, b string) int {
+	aNumber, aErr := strconv.ParseUint(a, 10, 64)
+	bNumber, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		if aNumber < bNumber {
+			return -1
+		} else if aNumber > bNumber {
+			return 1
+		}
+		return 0
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// PackageDir returns the directory with the sources of the package, or "" if the package does not belong
+// to this module, its vendor directory or one of its dependencies
+func (module *GoModule) PackageDir(packagePath string, useVendor bool) string {
+	if dir, ok := subPackageDir(module.Dir, module.Path, packagePath); ok {
+		return dir
+	}
+
+	if useVendor {
+		vendorDir := filepath.Join(module.Dir, "vendor", filepath.FromSlash(packagePath))
+		if info, err := os.Stat(vendorDir); err == nil && info.IsDir() {
+			return vendorDir
+		}
+	}
+
+	// The longest matching module path wins, e.g. example.com/a/b before example.com/a
+	modulePath := ""
+	for path := range module.Requires {
+		if len(path) > len(modulePath) && isSubPackage(path, packagePath) {
+			modulePath = path
+		}
+	}
+	for path := range module.Replaces {
+		if len(path) > len(modulePath) && isSubPackage(path, packagePath) {
+			modulePath = path
+		}
+	}
+	if modulePath == "" {
+		return ""
+	}
+
+	version := module.Requires[modulePath]
+	if replace, ok := module.Replaces[modulePath]; ok {
+		if isLocalModulePath(replace.Path) {
+			replaceDir := replace.Path
+			if !filepath.IsAbs(replaceDir) {
+				replaceDir = filepath.Join(module.Dir, replaceDir)
+			}
+			dir, _ := subPackageDir(replaceDir, modulePath, packagePath)
+			return dir
+		}
+		if replace.Version != "" {
+			version = replace.Version
+		}
+		packagePath = replace.Path + strings.TrimPrefix(packagePath, modulePath)
+		modulePath = replace.Path
+	}
+	if version == "" {
+		return ""
+	}
+
+	cachedModuleDir := filepath.Join(module.CacheDir, escapeModulePath(modulePath)+"@"+version)
+	dir, _ := subPackageDir(cachedModuleDir, modulePath, packagePath)
+	return dir
+}
+
+// ImportPath returns the import path of the package located in dir, which must be inside the module
+func (module *GoModule) ImportPath(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(module.Dir, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("Directory %s is outside of module %s (%s)", dir, module.Path, module.Dir)
+	}
+	if rel == "." {
+		return module.Path, nil
+	}
+	return module.Path + "/" + filepath.ToSlash(rel), nil
+}
+
+func isSubPackage(modulePath, packagePath string) bool {
+	return packagePath == modulePath || strings.HasPrefix(packagePath, modulePath+"/")
+}
+
+func subPackageDir(moduleDir, modulePath, packagePath string) (string, bool) {
+	if !isSubPackage(modulePath, packagePath) {
+		return "", false
+	}
+	return filepath.Join(moduleDir, filepath.FromSlash(strings.TrimPrefix(packagePath, modulePath))), true
+}
+
+func isLocalModulePath(path string) bool {
+	return filepath.IsAbs(path) || strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../") || path == "." || path == ".."
+}
+
+func unquoteModulePath(path string) string {
+	return strings.Trim(path, "\"`")
+}
+
+// escapeModulePath replaces every upper case letter with "!" followed by the lower case letter,
+// the same way the go command stores modules in the module cache
+func escapeModulePath(path string) string {
+	var escaped strings.Builder
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			escaped.WriteByte('!')
+			escaped.WriteRune(unicode.ToLower(r))
+		} else {
+			escaped.WriteRune(r)
+		}
+	}
+	return escaped.String()
+}
@@ -0,0 +1,117 @@
+package parser_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/yvasiyarov/swagger/parser"
+)
+
+type ModuleSuite struct {
+	suite.Suite
+	root   string
+	module *parser.GoModule
+}
+
+const exampleGoMod = `module example.com/service // our service
+
+go 1.21
+
+require (
+	github.com/Masterminds/semver v1.5.0
+	example.com/lib v0.2.0 // indirect
+)
+
+require "example.com/single" v1.0.0
+
+replace example.com/lib => ../lib
+replace (
+	example.com/single v1.0.0 => example.com/fork v1.1.0
+)
+`
+
+const exampleGoSum = `example.com/old v0.1.0 h1:abc=
+example.com/old v0.1.0/go.mod h1:abc=
+example.com/old v0.2.0 h1:def=
+example.com/semver v1.10.0 h1:abc=
+example.com/semver v1.10.0/go.mod h1:abc=
+example.com/semver v1.9.0 h1:def=
+example.com/semver v1.9.0/go.mod h1:def=
+example.com/pre v1.2.0 h1:abc=
+example.com/pre v1.2.0-rc.1 h1:def=
+example.com/semver/v2 v2.0.0-20230101000000-abcdef123456 h1:abc=
+example.com/semver/v2 v2.0.0-20221201000000-abcdef123456 h1:def=
+github.com/Masterminds/semver v1.9.0 h1:abc=
+`
+
+func (suite *ModuleSuite) SetupTest() {
+	var err error
+	suite.root, err = ioutil.TempDir("", "swagger-module")
+	assert.NoError(suite.T(), err, "Can not create temp dir")
+
+	suite.write("service/go.mod", exampleGoMod)
+	suite.write("service/go.sum", exampleGoSum)
+	suite.write("service/api/v1/api.go", "package v1")
+
+	suite.module, err = parser.FindGoModule(filepath.Join(suite.root, "service", "api", "v1"), suite.root)
+	assert.NoError(suite.T(), err, "Can not parse go.mod")
+	assert.NotNil(suite.T(), suite.module, "Can not find go.mod in parent dir")
+}
+
+func (suite *ModuleSuite) TearDownTest() {
+	os.RemoveAll(suite.root)
+}
+
+func (suite *ModuleSuite) write(name, content string) {
+	name = filepath.Join(suite.root, filepath.FromSlash(name))
+	assert.NoError(suite.T(), os.MkdirAll(filepath.Dir(name), 0777), "Can not create dir")
+	assert.NoError(suite.T(), ioutil.WriteFile(name, []byte(content), 0666), "Can not write file")
+}
+
+func (suite *ModuleSuite) TestParseGoModule() {
+	assert.Equal(suite.T(), "example.com/service", suite.module.Path, "Module path not parsed")
+	assert.Equal(suite.T(), filepath.Join(suite.root, "service"), suite.module.Dir, "Module dir not set")
+	assert.Equal(suite.T(), "v1.5.0", suite.module.Requires["github.com/Masterminds/semver"], "Require block not parsed")
+	assert.Equal(suite.T(), "v1.0.0", suite.module.Requires["example.com/single"], "Single require not parsed")
+	assert.Equal(suite.T(), "v0.2.0", suite.module.Requires["example.com/old"], "go.sum versions not parsed")
+	assert.Equal(suite.T(), "v1.10.0", suite.module.Requires["example.com/semver"], "Highest go.sum version must be used, v1.10.0 > v1.9.0")
+	assert.Equal(suite.T(), "v1.2.0", suite.module.Requires["example.com/pre"], "Release must be higher than its pre-releases")
+	assert.Equal(suite.T(), "v2.0.0-20230101000000-abcdef123456", suite.module.Requires["example.com/semver/v2"], "Latest pseudo-version must be used")
+	assert.Equal(suite.T(), "v1.5.0", suite.module.Requires["github.com/Masterminds/semver"], "go.mod requirements must win over go.sum")
+	assert.Equal(suite.T(), parser.ModuleReplace{Path: "../lib"}, suite.module.Replaces["example.com/lib"], "Single replace not parsed")
+	assert.Equal(suite.T(), parser.ModuleReplace{Path: "example.com/fork", Version: "v1.1.0"}, suite.module.Replaces["example.com/single"], "Replace block not parsed")
+}
+
+func (suite *ModuleSuite) TestPackageDir() {
+	cacheDir := suite.module.CacheDir
+	if os.Getenv("GOMODCACHE") == "" {
+		assert.Equal(suite.T(), filepath.Join(suite.root, "pkg", "mod"), cacheDir, "Module cache must default to $GOPATH/pkg/mod")
+	}
+
+	assert.Equal(suite.T(), filepath.Join(suite.root, "service", "api", "v1"), suite.module.PackageDir("example.com/service/api/v1", true), "Module package not resolved")
+	assert.Equal(suite.T(), filepath.Join(cacheDir, "github.com", "!masterminds", "semver@v1.5.0"), suite.module.PackageDir("github.com/Masterminds/semver", true), "Module cache path not escaped")
+	assert.Equal(suite.T(), filepath.Join(suite.root, "lib", "models"), suite.module.PackageDir("example.com/lib/models", true), "Local replace not resolved")
+	assert.Equal(suite.T(), filepath.Join(cacheDir, "example.com", "fork@v1.1.0", "sub"), suite.module.PackageDir("example.com/single/sub", true), "Module replace not resolved")
+	assert.Equal(suite.T(), "", suite.module.PackageDir("example.com/unknown", true), "Unknown package must not be resolved")
+
+	suite.write("service/vendor/example.com/vendored/vendored.go", "package vendored")
+	assert.Equal(suite.T(), filepath.Join(suite.root, "service", "vendor", "example.com", "vendored"), suite.module.PackageDir("example.com/vendored", true), "Vendored package not resolved")
+	assert.Equal(suite.T(), "", suite.module.PackageDir("example.com/vendored", false), "Vendor dir must be skipped if disabled")
+}
+
+func (suite *ModuleSuite) TestImportPath() {
+	importPath, err := suite.module.ImportPath(filepath.Join(suite.root, "service", "api", "v1"))
+	assert.NoError(suite.T(), err, "Can not get import path")
+	assert.Equal(suite.T(), "example.com/service/api/v1", importPath, "Import path not built from module path")
+
+	_, err = suite.module.ImportPath(suite.root)
+	assert.Error(suite.T(), err, "Directories outside of the module have no import path")
+}
+
+func TestModuleSuite(t *testing.T) {
+	suite.Run(t, &ModuleSuite{})
+}
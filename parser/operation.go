@@ -7,12 +7,15 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/yvasiyarov/swagger/utils"
 )
 
 type Operation struct {
 	HttpMethod       string            `json:"httpMethod"`
 	Nickname         string            `json:"nickname"`
 	Type             string            `json:"type"`
+	Format           string            `json:"format,omitempty"`
 	Items            OperationItems    `json:"items,omitempty"`
 	Summary          string            `json:"summary,omitempty"`
 	Notes            string            `json:"notes,omitempty"`
@@ -20,17 +23,30 @@ type Operation struct {
 	ResponseMessages []ResponseMessage `json:"responseMessages,omitempty"`
 	Consumes         []string          `json:"-"`
 	Produces         []string          `json:"produces,omitempty"`
-	Authorizations   []Authorization   `json:"authorizations,omitempty"`
-	Protocols        []Protocol        `json:"protocols,omitempty"`
-	Path             string            `json:"-"`
-	ForceResource    string            `json:"-"`
-	parser           *Parser
-	Models           []*Model `json:"-"`
-	packageName      string
+	// Deprecated: use Security, @Security is parsed into it. The field is no longer serialised,
+	// the "authorizations" of the JSON are the ones of Security
+	Authorizations []Authorization    `json:"-"`
+	Security       map[string][]Scope `json:"authorizations,omitempty"` // Scopes of the security definitions, by name
+	Deprecated     bool               `json:"deprecated,omitempty"`
+	Protocols      []Protocol         `json:"protocols,omitempty"`
+	Path           string             `json:"-"`
+	ForceResource  string             `json:"-"`
+	Tags           []string           `json:"-"`
+	parser         *Parser
+	Models         []*Model `json:"-"`
+	packageName    string
+	// Headers of responses which are not declared yet, by status code
+	pendingHeaders map[int][]ResponseHeader
+	// Examples of responses which are not declared yet, by status code and content type
+	pendingExamples map[int]map[string]string
+	// Kind of the @Description the following comment lines belong to
+	descriptionBlock string
+	blankLines       int
 }
 type OperationItems struct {
-	Ref  string `json:"$ref,omitempty"`
-	Type string `json:"type,omitempty"`
+	Ref    string `json:"$ref,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Format string `json:"format,omitempty"`
 }
 
 func NewOperation(p *Parser, packageName string) *Operation {
@@ -43,7 +59,9 @@ func NewOperation(p *Parser, packageName string) *Operation {
 
 func (operation *Operation) SetItemsType(itemsType string) {
 	operation.Items = OperationItems{}
-	if IsBasicType(itemsType) {
+	if swaggerType, format := SwaggerType(itemsType); swaggerType != "" {
+		operation.Items.Type, operation.Items.Format = swaggerType, format
+	} else if IsBasicType(itemsType) {
 		operation.Items.Type = itemsType
 	} else {
 		operation.Items.Ref = itemsType
@@ -51,6 +69,9 @@ func (operation *Operation) SetItemsType(itemsType string) {
 }
 
 func (operation *Operation) ParseComment(comment string) error {
+	if operation.parseDescriptionLine(comment) {
+		return nil
+	}
 	commentLine := strings.TrimSpace(strings.TrimLeft(comment, "//"))
 	if len(commentLine) == 0 {
 		return nil
@@ -63,14 +84,25 @@ func (operation *Operation) ParseComment(comment string) error {
 		}
 	case "@resource":
 		resource := strings.TrimSpace(commentLine[len(attribute):])
+		if resource == "" {
+			return fmt.Errorf("Can not parse resource comment \"%s\", skipped.", commentLine)
+		}
 		if resource[0:1] == "/" {
 			resource = resource[1:]
 		}
 		operation.ForceResource = resource
 	case "@title":
 		operation.Nickname = strings.TrimSpace(commentLine[len(attribute):])
-	case "@description":
+	case "@summary":
 		operation.Summary = strings.TrimSpace(commentLine[len(attribute):])
+	case "@description":
+		operation.setDescription(strings.TrimSpace(commentLine[len(attribute):]), descriptionText)
+	case "@description.markdown":
+		operation.setDescription(strings.TrimSpace(commentLine[len(attribute):]), descriptionMarkdown)
+	case "@description.file":
+		if err := operation.ParseDescriptionFileComment(strings.TrimSpace(commentLine[len(attribute):])); err != nil {
+			return err
+		}
 	case "@success", "@failure":
 		if err := operation.ParseResponseComment(strings.TrimSpace(commentLine[len(attribute):])); err != nil {
 			return err
@@ -87,6 +119,30 @@ func (operation *Operation) ParseComment(comment string) error {
 		if err := operation.ParseProduceComment(strings.TrimSpace(commentLine[len(attribute):])); err != nil {
 			return err
 		}
+	case "@header":
+		if err := operation.ParseHeaderComment(strings.TrimSpace(commentLine[len(attribute):])); err != nil {
+			return err
+		}
+	case "@tags":
+		if err := operation.ParseTagsComment(strings.TrimSpace(commentLine[len(attribute):])); err != nil {
+			return err
+		}
+	case "@deprecated":
+		operation.Deprecated = true
+	case "@example":
+		if err := operation.ParseExampleComment(strings.TrimSpace(commentLine[len(attribute):])); err != nil {
+			return err
+		}
+	case "@security":
+		if err := operation.ParseSecurityComment(strings.TrimSpace(commentLine[len(attribute):])); err != nil {
+			return err
+		}
+	case "@subapi":
+		// Parsed by ParseSubApiDescription
+	default:
+		if strings.HasPrefix(attribute, "@") && !isGeneralApiInfoAnnotation(strings.ToLower(attribute)) {
+			return fmt.Errorf("Unknown annotation %s, skipped.", attribute)
+		}
 	}
 
 	operation.Models = operation.getUniqueModels()
@@ -113,7 +169,9 @@ func (operation *Operation) getUniqueModels() []*Model {
 func (operation *Operation) registerType(typeName string) (string, error) {
 	registerType := ""
 
-	if translation, ok := typeDefTranslations[typeName]; ok {
+	if modelName, overrides := splitFieldOverrides(typeName); len(overrides) > 0 {
+		return operation.registerComposite(modelName, overrides)
+	} else if translation, ok := typeDefTranslations[typeName]; ok {
 		registerType = translation
 	} else if IsBasicType(typeName) {
 		registerType = typeName
@@ -138,6 +196,12 @@ func (operation *Operation) registerType(typeName string) (string, error) {
 	return registerType, nil
 }
 
+var (
+	paramEnums   = regexp.MustCompile(`Enums\(([^)]*)\)`)
+	paramDefault = regexp.MustCompile(`default\(([^)]*)\)`)
+	paramExample = regexp.MustCompile(`example\(([^)]*)\)`)
+)
+
 // Parse params return []string of param properties
 // @Param	queryText		form	      string	  true		        "The email for login"
 // 			[param name]    [param type] [data type]  [is mandatory?]   [Comment]
@@ -159,10 +223,34 @@ func (operation *Operation) ParseParamComment(commentLine string) error {
 		swaggerParameter.ParamType = matches[2]
 		swaggerParameter.Type = typeName
 		swaggerParameter.DataType = typeName
+		if swaggerType, format := SwaggerType(typeName); swaggerType != "" {
+			swaggerParameter.Type, swaggerParameter.Format = swaggerType, format
+		}
 		requiredText := strings.ToLower(matches[4])
 		swaggerParameter.Required = (requiredText == "true" || requiredText == "required")
 		swaggerParameter.Description = matches[5]
 
+		// Named basic types (type Status string) get their constants as enum,
+		// unless they are listed after the description: Enums(a,b,c)
+		if typeName != matches[3] && IsBasicType(typeName) {
+			swaggerParameter.Enum = operation.typeEnum(matches[3])
+		}
+		attributes := paramString[re.FindStringIndex(paramString)[1]:]
+		if enums := paramEnums.FindStringSubmatch(attributes); len(enums) == 2 {
+			swaggerParameter.Enum = nil
+			for _, value := range strings.Split(enums[1], ",") {
+				if value = strings.TrimSpace(value); value != "" {
+					swaggerParameter.Enum = append(swaggerParameter.Enum, value)
+				}
+			}
+		}
+		if defaultValue := paramDefault.FindStringSubmatch(attributes); len(defaultValue) == 2 {
+			swaggerParameter.DefaultValue = strings.TrimSpace(defaultValue[1])
+		}
+		if example := paramExample.FindStringSubmatch(attributes); len(example) == 2 {
+			swaggerParameter.Example = strings.TrimSpace(example[1])
+		}
+
 		operation.Parameters = append(operation.Parameters, swaggerParameter)
 	}
 
@@ -227,7 +315,7 @@ func (operation *Operation) ParseRouterComment(commentLine string) error {
 
 // @Success 200 {object} model.OrderRow "Error message, if code != 200"
 func (operation *Operation) ParseResponseComment(commentLine string) error {
-	re := regexp.MustCompile(`([\d]+)[\s]+([\w\{\}]+)[\s]+([\w\-\.\/]+)[^"]*(.*)?`)
+	re := regexp.MustCompile(`([\d]+)[\s]+([\w\{\}]+)[\s]+([\w\-\.\/\[\]\{\},=]+)[^"]*(.*)?`)
 	var matches []string
 
 	if matches = re.FindStringSubmatch(commentLine); len(matches) != 5 {
@@ -254,11 +342,119 @@ func (operation *Operation) ParseResponseComment(commentLine string) error {
 		if matches[2] == "{array}" {
 			operation.SetItemsType(typeName)
 			operation.Type = "array"
+		} else if swaggerType, format := SwaggerType(typeName); swaggerType != "" {
+			operation.Type, operation.Format = swaggerType, format
 		} else {
 			operation.Type = typeName
 		}
 	}
 
+	response.Headers = operation.pendingHeaders[response.Code]
+	delete(operation.pendingHeaders, response.Code)
+	response.Examples = operation.pendingExamples[response.Code]
+	delete(operation.pendingExamples, response.Code)
+
 	operation.ResponseMessages = append(operation.ResponseMessages, response)
 	return nil
 }
+
+// @Tags users,admin
+func (operation *Operation) ParseTagsComment(commentLine string) error {
+	for _, tag := range strings.Split(commentLine, ",") {
+		if tag = strings.TrimSpace(tag); tag == "" {
+			continue
+		}
+		if tag[0:1] == "/" {
+			tag = tag[1:]
+		}
+		if !utils.StringSliceContains(operation.Tags, tag) {
+			operation.Tags = append(operation.Tags, tag)
+		}
+	}
+	if len(operation.Tags) == 0 {
+		return fmt.Errorf("Can not parse tags comment \"%s\", skipped.", commentLine)
+	}
+	return nil
+}
+
+var headerComment = regexp.MustCompile(`^([\d,]+)[\s]+\{(\w+)\}[\s]+([\w\-]+)[\s]*(?:"([^"]*)")?$`)
+
+// @Header 200,206 {integer} X-Total-Count "Total number of rows"
+func (operation *Operation) ParseHeaderComment(commentLine string) error {
+	matches := headerComment.FindStringSubmatch(commentLine)
+	if len(matches) != 5 {
+		return fmt.Errorf("Can not parse header comment \"%s\", skipped.", commentLine)
+	}
+
+	header := ResponseHeader{Name: matches[3], Type: matches[2], Description: matches[4]}
+	if swaggerType, format := SwaggerType(header.Type); swaggerType != "" {
+		header.Type, header.Format = swaggerType, format
+	}
+
+	for _, codeText := range strings.Split(matches[1], ",") {
+		code, err := strconv.Atoi(codeText)
+		if err != nil {
+			return fmt.Errorf("Can not parse header comment \"%s\", skipped.", commentLine)
+		}
+
+		// Headers are attached to the response with the same code, which can follow them
+		attached := false
+		for i := range operation.ResponseMessages {
+			if operation.ResponseMessages[i].Code == code {
+				operation.ResponseMessages[i].Headers = append(operation.ResponseMessages[i].Headers, header)
+				attached = true
+			}
+		}
+		if !attached {
+			if operation.pendingHeaders == nil {
+				operation.pendingHeaders = make(map[int][]ResponseHeader)
+			}
+			operation.pendingHeaders[code] = append(operation.pendingHeaders[code], header)
+		}
+	}
+	return nil
+}
+
+var exampleComment = regexp.MustCompile(`^([\d,]+)[\s]+([\w\-\.\+]+/[\w\-\.\+]+)[\s]+(\S+)$`)
+
+// @Example 200 application/json examples/order.json
+func (operation *Operation) ParseExampleComment(commentLine string) error {
+	matches := exampleComment.FindStringSubmatch(commentLine)
+	if len(matches) != 4 {
+		return fmt.Errorf("Can not parse example comment \"%s\", skipped.", commentLine)
+	}
+	content, err := operation.readPackageFile(matches[3])
+	if err != nil {
+		return fmt.Errorf("Can not read example file: %v", err)
+	}
+	example := strings.TrimSpace(string(content))
+
+	for _, codeText := range strings.Split(matches[1], ",") {
+		code, err := strconv.Atoi(codeText)
+		if err != nil {
+			return fmt.Errorf("Can not parse example comment \"%s\", skipped.", commentLine)
+		}
+
+		// Like headers, examples are attached to the response with the same code, which can follow them
+		attached := false
+		for i := range operation.ResponseMessages {
+			if operation.ResponseMessages[i].Code == code {
+				if operation.ResponseMessages[i].Examples == nil {
+					operation.ResponseMessages[i].Examples = make(map[string]string)
+				}
+				operation.ResponseMessages[i].Examples[matches[2]] = example
+				attached = true
+			}
+		}
+		if !attached {
+			if operation.pendingExamples == nil {
+				operation.pendingExamples = make(map[int]map[string]string)
+			}
+			if operation.pendingExamples[code] == nil {
+				operation.pendingExamples[code] = make(map[string]string)
+			}
+			operation.pendingExamples[code][matches[2]] = example
+		}
+	}
+	return nil
+}
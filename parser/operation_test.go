@@ -1,6 +1,9 @@
 package parser_test
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -27,7 +30,7 @@ func (suite *OperationSuite) TestNewApi() {
 func (suite *OperationSuite) TestSetItemsType() {
 	op := parser.NewOperation(suite.parser, "test")
 	op.SetItemsType("int")
-	assert.Equal(suite.T(), op.Items.Type, "int", "Can no set item type to simple type")
+	assert.Equal(suite.T(), op.Items.Type, "integer", "Can no set item type to simple type")
 
 	op2 := parser.NewOperation(suite.parser, "test")
 	op2.SetItemsType("SomeType")
@@ -113,6 +116,108 @@ func (suite *OperationSuite) TestParseResponseComment() {
 	assert.Equal(suite.T(), op3.Items.Type, "string", "Can not parse response comment")
 }
 
+func (suite *OperationSuite) TestParseHeaderComment() {
+	op := parser.NewOperation(suite.parser, "test")
+	assert.Nil(suite.T(), op.ParseComment(`// @Header 200,206 {int} X-Total-Count "Total number of rows"`), "Can not parse header comment")
+	assert.Nil(suite.T(), op.ParseComment(`// @Success 200 {array} string`), "Can not parse response comment")
+	assert.Nil(suite.T(), op.ParseComment(`// @Header 200 {string} ETag`), "Can not parse header comment")
+	assert.NotNil(suite.T(), op.ParseHeaderComment(`200 X-Total-Count "No type"`), "Header without type must be skipped")
+
+	assert.Len(suite.T(), op.ResponseMessages, 1, "Can not parse response comment")
+	assert.Equal(suite.T(), []parser.ResponseHeader{
+		{Name: "X-Total-Count", Type: "integer", Description: "Total number of rows"},
+		{Name: "ETag", Type: "string"},
+	}, op.ResponseMessages[0].Headers, "Headers not attached to the response")
+
+	assert.Nil(suite.T(), op.ParseComment(`// @Success 206 {array} string`), "Can not parse response comment")
+	assert.Len(suite.T(), op.ResponseMessages[1].Headers, 1, "Headers declared before the response not attached")
+}
+
+func (suite *OperationSuite) parseComments(op *parser.Operation, comments string) {
+	for _, line := range strings.Split(strings.TrimSpace(comments), "\n") {
+		assert.Nil(suite.T(), op.ParseComment(line), "Can not parse operation comment")
+	}
+}
+
+func (suite *OperationSuite) TestParseDescription() {
+	op := parser.NewOperation(suite.parser, "test")
+	suite.parseComments(op, `
+// @Title listOrders
+// @Description Return orders of the user,
+// newest first.
+//
+// Deleted orders are skipped.
+// @Router /orders [get]`)
+	assert.Equal(suite.T(), "Return orders of the user,", op.Summary, "First line of description must be the summary")
+	assert.Equal(suite.T(), "Return orders of the user,\nnewest first.\n\nDeleted orders are skipped.", op.Notes, "Continuation lines not parsed")
+	assert.Equal(suite.T(), "/orders", op.Path, "Annotations must end the description")
+
+	op = parser.NewOperation(suite.parser, "test")
+	suite.parseComments(op, `
+// @Description.markdown
+// Orders are **paged**:
+//
+//     GET /orders?page=2
+// @Summary List orders`)
+	assert.Equal(suite.T(), "List orders", op.Summary, "Summary not parsed")
+	assert.Equal(suite.T(), "Orders are **paged**:\n\n    GET /orders?page=2", op.Notes, "Markdown block not parsed")
+
+	dir, err := ioutil.TempDir("", "swagger-description")
+	assert.NoError(suite.T(), err, "Can not create temp dir")
+	defer os.RemoveAll(dir)
+	fileName := filepath.Join(dir, "orders.md")
+	assert.NoError(suite.T(), ioutil.WriteFile(fileName, []byte("# Orders\n\nAll orders.\n"), 0666), "Can not write file")
+
+	op = parser.NewOperation(suite.parser, "test")
+	suite.parseComments(op, "// @Summary List orders\n// @Description.file "+fileName)
+	assert.Equal(suite.T(), "# Orders\n\nAll orders.", op.Notes, "Description file not loaded")
+	assert.Error(suite.T(), op.ParseComment("// @Description.file "+filepath.Join(dir, "missing.md")), "Missing description file must be reported")
+}
+
+func (suite *OperationSuite) TestParseExamples() {
+	dir, err := ioutil.TempDir("", "swagger-example")
+	assert.NoError(suite.T(), err, "Can not create temp dir")
+	defer os.RemoveAll(dir)
+	fileName := filepath.Join(dir, "order.json")
+	assert.NoError(suite.T(), ioutil.WriteFile(fileName, []byte("{\"id\": 1}\n"), 0666), "Can not write file")
+
+	op := parser.NewOperation(suite.parser, "test")
+	suite.parseComments(op, `
+// @Param   page    query   int     false   "Page number" default(1) example(3)
+// @Param   order   body    string  true    "Order" example({"id": 1})
+// @Example 200,404 application/json `+fileName+`
+// @Success 200 {object} string
+// @Example 200 text/plain `+fileName)
+	assert.Equal(suite.T(), "1", op.Parameters[0].DefaultValue, "Default value not parsed")
+	assert.Equal(suite.T(), "3", op.Parameters[0].Example, "Param example not parsed")
+	assert.Equal(suite.T(), `{"id": 1}`, op.Parameters[1].Example, "Param example not parsed")
+	assert.Equal(suite.T(), map[string]string{
+		parser.ContentTypeJson:  `{"id": 1}`,
+		parser.ContentTypePlain: `{"id": 1}`,
+	}, op.ResponseMessages[0].Examples, "Examples not attached to the response")
+
+	assert.Error(suite.T(), op.ParseExampleComment("200 "+fileName), "Example without content type must be skipped")
+	assert.Error(suite.T(), op.ParseExampleComment("200 application/json "+filepath.Join(dir, "missing.json")), "Missing example file must be reported")
+}
+
+func (suite *OperationSuite) TestParseTags() {
+	op := parser.NewOperation(suite.parser, "test")
+	assert.Nil(suite.T(), op.ParseComment("// @Tags users, /admin,users"), "Can not parse tags comment")
+	assert.Equal(suite.T(), []string{"users", "admin"}, op.Tags, "Tags not parsed")
+	assert.NotNil(suite.T(), parser.NewOperation(suite.parser, "test").ParseTagsComment(" , "), "Empty tags must be skipped")
+}
+
+func (suite *OperationSuite) TestParseDeprecated() {
+	op := parser.NewOperation(suite.parser, "test")
+	suite.parseComments(op, `
+// @Title listOrders
+// @Description List orders
+// @Deprecated
+// @Router /orders [get]`)
+	assert.True(suite.T(), op.Deprecated, "@Deprecated not parsed")
+	assert.Equal(suite.T(), "List orders", op.Notes, "@Deprecated must end the description")
+}
+
 func (suite *OperationSuite) TestParseComment() {
 	operationComment := `
 // @Title getOrderByNumber
@@ -150,7 +255,7 @@ func (suite *OperationSuite) TestParseComment() {
 	assert.Equal(suite.T(), op.ResponseMessages[0].Code, 200, "Can not parse operation comment")
 	assert.Equal(suite.T(), op.ResponseMessages[0].Message, "", "Can not parse operation comment")
 	assert.Equal(suite.T(), op.Type, "array", "Can not parse operation comment")
-	assert.Equal(suite.T(), op.Items.Type, "int", "Can not parse operation comment")
+	assert.Equal(suite.T(), op.Items.Type, "integer", "Can not parse operation comment")
 
 	assert.Equal(suite.T(), op.ResponseMessages[1].Code, 400, "Can not parse operation comment")
 	assert.Equal(suite.T(), op.ResponseMessages[1].Message, "Order ID must be specified", "Can not parse operation comment")
@@ -0,0 +1,100 @@
+package parser_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/yvasiyarov/swagger/parser"
+)
+
+type OrderSuite struct {
+	suite.Suite
+	root string
+}
+
+var orderSources = map[string]string{
+	"go.mod": "module example.com/order\n",
+	"api/a_users.go": `package api
+
+// @Router /users/{id} [get]
+func GetUser() {}
+
+// @Router /users [post]
+func CreateUser() {}
+
+// @Router /users [get]
+func ListUsers() {}
+`,
+	"api/b_orders.go": `package api
+
+// @Router /orders [get]
+func ListOrders() {}
+`,
+}
+
+func (suite *OrderSuite) SetupSuite() {
+	var err error
+	suite.root, err = ioutil.TempDir("", "swagger-order")
+	assert.NoError(suite.T(), err, "Can not create temp dir")
+
+	for name, content := range orderSources {
+		name = filepath.Join(suite.root, filepath.FromSlash(name))
+		assert.NoError(suite.T(), os.MkdirAll(filepath.Dir(name), 0777), "Can not create dir")
+		assert.NoError(suite.T(), ioutil.WriteFile(name, []byte(content), 0666), "Can not write file")
+	}
+}
+
+func (suite *OrderSuite) TearDownSuite() {
+	os.RemoveAll(suite.root)
+}
+
+func (suite *OrderSuite) parse(sortOutput bool) *parser.Parser {
+	p, err := parser.NewParser(filepath.Join(suite.root, "api"), "", "^$", "", false)
+	assert.NoError(suite.T(), err, "Unable to initialize parser")
+	p.SortOutput = sortOutput
+	assert.NoError(suite.T(), p.ParseApi(), "Can not parse API")
+	return p
+}
+
+func (suite *OrderSuite) order(p *parser.Parser) []string {
+	var order []string
+	for _, apiRef := range p.Listing.Apis {
+		for _, api := range p.TopLevelApis[apiRef.Path[1:]].Apis {
+			for _, op := range api.Operations {
+				order = append(order, op.HttpMethod+" "+op.Path)
+			}
+		}
+	}
+	return order
+}
+
+func (suite *OrderSuite) TestSourceOrder() {
+	p := suite.parse(false)
+	assert.Equal(suite.T(), []string{
+		"GET /users/{id}",
+		"POST /users",
+		"GET /users",
+		"GET /orders",
+	}, suite.order(p), "Operations not in the order of the files and declarations")
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(suite.T(), suite.order(p), suite.order(suite.parse(false)), "Source order changed between runs")
+	}
+}
+
+func (suite *OrderSuite) TestSortOutput() {
+	assert.Equal(suite.T(), []string{
+		"GET /orders",
+		"GET /users",
+		"POST /users",
+		"GET /users/{id}",
+	}, suite.order(suite.parse(true)), "Operations not sorted by path and method")
+}
+
+func TestOrderSuite(t *testing.T) {
+	suite.Run(t, &OrderSuite{})
+}
@@ -2,13 +2,16 @@ package parser
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"go/ast"
 	goparser "go/parser"
 	"go/token"
+	"go/types"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -25,18 +28,44 @@ type Parser struct {
 	Listing                           *ResourceListing
 	TopLevelApis                      map[string]*ApiDeclaration
 	PackagesCache                     map[string]map[string]*ast.Package
+	FileSet                           *token.FileSet
+	TypesPackages                     map[string]*types.Package
+	TypesInfos                        map[string]*types.Info
 	CurrentPackage                    string
 	TypeDefinitions                   map[string]map[string]*ast.TypeSpec
+	TypeConstants                     map[string]map[string][]*ast.Ident
+	SwaggerTypes                      map[string]map[string]string
+	DeprecatedTypes                   map[string]map[string]bool
 	PackagePathCache                  map[string]string
 	PackageImports                    map[string]map[string][]string
 	BasePath, ControllerClass, Ignore string
 	IsController                      func(*ast.FuncDecl, string) bool
+	// Swagger types of types encoded as a primitive, by qualified name like database/sql.NullString
 	TypesImplementingMarshalInterface map[string]string
 
+	// DiscoverRoutes makes the parser take the paths and methods of operations from the router registration code,
+	// @Router is only needed for handlers which are not found in Routes
+	DiscoverRoutes bool
+	Routes         map[string][]*Route
+
+	// SortOutput orders resources and apis by path and operations by method, instead of the order
+	// of their declarations in the sources
+	SortOutput bool
+
+	// CollectErrors makes the parser record failures in Errors and go on, instead of stopping at the first one
+	CollectErrors bool
+	Errors        ParseErrors
+	// Warnings are annotations which were skipped or are inconsistent, they do not stop parsing
+	Warnings          ParseErrors
+	nicknames         map[string]string
+	checkedMarshalers map[string]bool
+	instanceTypes     map[string]string
+
 	VendoringPath    string
 	DisableVendoring bool
 	GoRoot           string
 	GoPath           string
+	Module           *GoModule
 }
 
 // It must return true if funcDeclaration is controller. We will try to parse only comments before controllers
@@ -48,10 +77,8 @@ func isController(funcDeclaration *ast.FuncDecl, controllerClass string) bool {
 	if funcDeclaration.Recv != nil && len(funcDeclaration.Recv.List) > 0 {
 		if starExpression, ok := funcDeclaration.Recv.List[0].Type.(*ast.StarExpr); ok {
 			receiverName := fmt.Sprint(starExpression.X)
-			matched, err := regexp.MatchString(string(controllerClass), receiverName)
-			if err != nil {
-				log.Fatalf("The -controllerClass argument is not a valid regular expression: %v\n", err)
-			}
+			// controllerClass is validated by NewParser
+			matched, _ := regexp.MatchString(string(controllerClass), receiverName)
 			return matched
 		}
 	}
@@ -64,8 +91,42 @@ func NewParser(apiPackages, controllerClass, ignoreParam, vendoringPath string,
 		return nil, err
 	}
 
+	if _, err := regexp.Compile(controllerClass); err != nil {
+		return nil, fmt.Errorf("The -controllerClass argument is not a valid regular expression: %v", err)
+	}
+	if _, err := regexp.Compile(ignoreParam); err != nil {
+		return nil, fmt.Errorf("The -ignore argument is not a valid regular expression: %v", err)
+	}
+
 	packages := strings.Split(apiPackages, ",")
 
+	// API packages can be given as directories, in that case the module they belong to
+	// is used to translate them to import paths. Otherwise the current directory decides the module
+	var module *GoModule
+	for i, packageName := range packages {
+		if !isLocalModulePath(packageName) {
+			continue
+		}
+		if module == nil {
+			if module, err = FindGoModule(packageName, gopath); err != nil {
+				return nil, err
+			} else if module == nil {
+				return nil, fmt.Errorf("Can not find go.mod for API package directory %s", packageName)
+			}
+		}
+		if packages[i], err = module.ImportPath(packageName); err != nil {
+			return nil, err
+		}
+	}
+	if module == nil {
+		if module, err = FindGoModule(".", gopath); err != nil {
+			return nil, err
+		}
+	}
+	if module != nil {
+		log.Debugf("Using module %s (%s)", module.Path, module.Dir)
+	}
+
 	return &Parser{
 		APIPackages: packages,
 		Listing: &ResourceListing{
@@ -79,36 +140,80 @@ func NewParser(apiPackages, controllerClass, ignoreParam, vendoringPath string,
 		DisableVendoring: disableVendoring,
 		GoPath:           gopath,
 		GoRoot:           goroot,
+		Module:           module,
 		PackagesCache:    make(map[string]map[string]*ast.Package),
+		FileSet:          token.NewFileSet(),
+		TypesPackages:    make(map[string]*types.Package),
+		TypesInfos:       make(map[string]*types.Info),
 		TopLevelApis:     make(map[string]*ApiDeclaration),
 		TypeDefinitions:  make(map[string]map[string]*ast.TypeSpec),
+		TypeConstants:    make(map[string]map[string][]*ast.Ident),
+		SwaggerTypes:     make(map[string]map[string]string),
+		DeprecatedTypes:  make(map[string]map[string]bool),
 		PackagePathCache: make(map[string]string),
 		PackageImports:   make(map[string]map[string][]string),
 		TypesImplementingMarshalInterface: map[string]string{
-			"NullString":  "string",
-			"NullInt64":   "int",
-			"NullFloat64": "float",
-			"NullBool":    "bool",
+			"database/sql.NullString":  "string",
+			"database/sql.NullInt64":   "integer int64",
+			"database/sql.NullFloat64": "number double",
+			"database/sql.NullBool":    "boolean",
 		},
 	}, nil
 }
 
 func (parser *Parser) IsImplementMarshalInterface(typeName string) bool {
-	_, ok := parser.TypesImplementingMarshalInterface[typeName]
+	_, _, ok := parser.MarshalerType(typeName)
 	return ok
 }
 
+// General API info annotations, they are parsed by ParseGeneralApiInfo and skipped in the comments of operations
+var generalApiInfoAnnotations = map[string]bool{
+	"@apiversion":        true,
+	"@apititle":          true,
+	"@apidescription":    true,
+	"@termsofserviceurl": true,
+	"@contact":           true,
+	"@licenseurl":        true,
+	"@license":           true,
+	"@basepath":          true,
+	"@tag":               true,
+	"@environment":       true,
+	"@in":                true,
+	"@name":              true,
+	"@authorizationurl":  true,
+	"@tokenurl":          true,
+}
+
+// Prefixes of the dotted general API info annotations, like @SecurityDefinitions.apikey and @Scope.write
+var generalApiInfoPrefixes = []string{"@securitydefinitions.", "@scope."}
+
+// isGeneralApiInfoAnnotation checks if the lower case attribute is an annotation of the general API info
+func isGeneralApiInfoAnnotation(attribute string) bool {
+	if generalApiInfoAnnotations[attribute] {
+		return true
+	}
+	for _, prefix := range generalApiInfoPrefixes {
+		if strings.HasPrefix(attribute, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 //Read web/main.go to get General info
-func (parser *Parser) ParseGeneralApiInfo(mainApiFile string) {
+func (parser *Parser) ParseGeneralApiInfo(mainApiFile string) error {
 
 	fileSet := token.NewFileSet()
 	fileTree, err := goparser.ParseFile(fileSet, mainApiFile, nil, goparser.ParseComments)
 	if err != nil {
-		log.Fatalf("Can not parse general API information: %v\n", err)
+		return &ParseError{File: mainApiFile, Err: fmt.Errorf("Can not parse general API information: %v", err)}
 	}
 
 	parser.Listing.BasePath = "{{.}}"
 	parser.Listing.SwaggerVersion = SwaggerVersion
+	var authorization *SecurityDefinition
+	// @SecurityDefinitions annotations, by the definitions they declare
+	definitions := make(map[*SecurityDefinition]string)
 	if fileTree.Comments != nil {
 		for _, comment := range fileTree.Comments {
 			for _, commentLine := range strings.Split(comment.Text(), "\n") {
@@ -130,26 +235,45 @@ func (parser *Parser) ParseGeneralApiInfo(mainApiFile string) {
 					parser.Listing.Infos.License = strings.TrimSpace(commentLine[len(attribute):])
 				case "@basepath":
 					parser.Listing.BasePath = strings.TrimSpace(commentLine[len(attribute):])
+				case "@tag":
+					if err := parser.ParseTagComment(strings.TrimSpace(commentLine[len(attribute):])); err != nil {
+						parser.Warnings = append(parser.Warnings, &ParseError{File: mainApiFile, Annotation: strings.TrimSpace(commentLine), Err: err})
+					}
+				case "@environment":
+					if err := parser.ParseEnvironmentComment(strings.TrimSpace(commentLine[len(attribute):])); err != nil {
+						parser.Warnings = append(parser.Warnings, &ParseError{File: mainApiFile, Annotation: strings.TrimSpace(commentLine), Err: err})
+					}
+				default:
+					if !isGeneralApiInfoAnnotation(attribute) {
+						continue
+					}
+					if authorization, err = parser.ParseSecurityDefinitionComment(authorization, commentLine); err != nil {
+						parser.Warnings = append(parser.Warnings, &ParseError{File: mainApiFile, Annotation: strings.TrimSpace(commentLine), Err: err})
+					} else if _, ok := definitions[authorization]; authorization != nil && !ok {
+						definitions[authorization] = strings.TrimSpace(commentLine)
+					}
 				}
 			}
 		}
 	}
+	parser.checkSecurityDefinitions(mainApiFile, definitions)
+	return nil
 }
 
-func (parser *Parser) GetResourceListingJson() []byte {
+func (parser *Parser) GetResourceListingJson() ([]byte, error) {
 	json, err := json.MarshalIndent(parser.Listing, "", "    ")
 	if err != nil {
-		log.Fatalf("Can not serialise ResourceListing to JSON: %v\n", err)
+		return nil, fmt.Errorf("Can not serialise ResourceListing to JSON: %v\n", err)
 	}
-	return json
+	return json, nil
 }
 
-func (parser *Parser) GetApiDescriptionJson() []byte {
+func (parser *Parser) GetApiDescriptionJson() ([]byte, error) {
 	json, err := json.MarshalIndent(parser.TopLevelApis, "", "    ")
 	if err != nil {
-		log.Fatalf("Can not serialise []ApiDescription to JSON: %v\n", err)
+		return nil, fmt.Errorf("Can not serialise []ApiDescription to JSON: %v\n", err)
 	}
-	return json
+	return json, nil
 }
 
 func (parser *Parser) CheckRealPackagePath(packagePath string) string {
@@ -194,6 +318,20 @@ func (parser *Parser) CheckRealPackagePath(packagePath string) string {
 		}
 	}
 
+	// next, check the current module, its vendor dir and dependencies from the module cache
+	if parser.Module != nil {
+		useVendor := !parser.DisableVendoring && parser.VendoringPath == ""
+		if moduleDir := parser.Module.PackageDir(packagePath, useVendor); moduleDir != "" {
+			if evaluatedPath, err := filepath.EvalSymlinks(moduleDir); err == nil {
+				if _, err := os.Stat(evaluatedPath); err == nil {
+					log.Debugf("Found pkg '%v' in module %v (%v)", packagePath, parser.Module.Path, evaluatedPath)
+					parser.PackagePathCache[packagePath] = evaluatedPath
+					return evaluatedPath
+				}
+			}
+		}
+	}
+
 	// next, check GOPATH
 	gopathsList := filepath.SplitList(parser.GoPath)
 	for _, path := range gopathsList {
@@ -215,6 +353,15 @@ func (parser *Parser) CheckRealPackagePath(packagePath string) string {
 		}
 	}
 
+	// next, check packages vendored by the standard library (GOROOT/src/vendor)
+	if evaluatedPath, err := filepath.EvalSymlinks(filepath.Join(parser.GoRoot, "src", "vendor", packagePath)); err == nil {
+		if _, err := os.Stat(evaluatedPath); err == nil {
+			log.Debugf("Found pkg '%v' in GOROOT vendor dir (%v)", packagePath, evaluatedPath)
+			parser.PackagePathCache[packagePath] = evaluatedPath
+			return evaluatedPath
+		}
+	}
+
 	// next, check GOROOT (/src/pkg) (for golang < v1.4)
 	if evaluatedPath, err := filepath.EvalSymlinks(filepath.Join(parser.GoRoot, "src", "pkg", packagePath)); err == nil {
 		if _, err := os.Stat(evaluatedPath); err == nil {
@@ -227,43 +374,73 @@ func (parser *Parser) CheckRealPackagePath(packagePath string) string {
 	return ""
 }
 
-func (parser *Parser) GetRealPackagePath(packagePath string) string {
+func (parser *Parser) GetRealPackagePath(packagePath string) (string, error) {
 	pkgRealpath := parser.CheckRealPackagePath(packagePath)
 	if pkgRealpath == "" {
-		log.Fatalf("Can not find package %s", packagePath)
+		return "", fmt.Errorf("Can not find package %s", packagePath)
 	}
 
-	return pkgRealpath
+	return pkgRealpath, nil
 }
 
-func (parser *Parser) GetPackageAst(packagePath string) map[string]*ast.Package {
+func (parser *Parser) GetPackageAst(packagePath string) (map[string]*ast.Package, error) {
 	if cache, ok := parser.PackagesCache[packagePath]; ok {
-		return cache
+		return cache, nil
 	} else {
-		fileSet := token.NewFileSet()
-
-		astPackages, err := goparser.ParseDir(fileSet, packagePath, ParserFileFilter, goparser.ParseComments)
+		astPackages, err := goparser.ParseDir(parser.FileSet, packagePath, ParserFileFilter, goparser.ParseComments)
 		if err != nil {
-			log.Fatalf("Parse of %s pkg cause error: %s\n", packagePath, err)
+			return nil, parser.newParseError(token.NoPos, "", "", err)
 		}
 		parser.PackagesCache[packagePath] = astPackages
-		return astPackages
+		return astPackages, nil
+	}
+}
+
+// sortedFiles returns the files of the packages ordered by package and file name,
+// so declarations are always visited in the same order
+func sortedFiles(astPackages map[string]*ast.Package) []*ast.File {
+	packageNames := make([]string, 0, len(astPackages))
+	for packageName := range astPackages {
+		packageNames = append(packageNames, packageName)
+	}
+	sort.Strings(packageNames)
+
+	var files []*ast.File
+	for _, packageName := range packageNames {
+		fileNames := make([]string, 0, len(astPackages[packageName].Files))
+		for fileName := range astPackages[packageName].Files {
+			fileNames = append(fileNames, fileName)
+		}
+		sort.Strings(fileNames)
+		for _, fileName := range fileNames {
+			files = append(files, astPackages[packageName].Files[fileName])
+		}
 	}
+	return files
 }
 
 func (parser *Parser) AddOperation(op *Operation) {
-	path := []string{}
-	for _, pathPart := range strings.Split(op.Path, "/") {
-		if pathPart = strings.TrimSpace(pathPart); pathPart != "" {
-			path = append(path, pathPart)
+	// Tagged operations belong to every resource they are tagged with
+	resources := op.Tags
+	if len(resources) == 0 {
+		if op.ForceResource != "" {
+			resources = []string{op.ForceResource}
+		} else {
+			for _, pathPart := range strings.Split(op.Path, "/") {
+				if pathPart = strings.TrimSpace(pathPart); pathPart != "" {
+					resources = []string{pathPart}
+					break
+				}
+			}
 		}
 	}
 
-	resource := path[0]
-	if op.ForceResource != "" {
-		resource = op.ForceResource
+	for _, resource := range resources {
+		parser.addResourceOperation(resource, op)
 	}
+}
 
+func (parser *Parser) addResourceOperation(resource string, op *Operation) {
 	api, ok := parser.TopLevelApis[resource]
 	if !ok {
 		api = NewApiDeclaration()
@@ -287,25 +464,96 @@ func (parser *Parser) AddOperation(op *Operation) {
 			Path:        api.ResourcePath,
 			Description: op.Summary,
 		}
+		if description, ok := parser.Listing.TagDescriptions[resource]; ok {
+			apiRef.Description = description
+		}
 		parser.Listing.Apis = append(parser.Listing.Apis, apiRef)
 	}
 
 	api.AddOperation(op)
 }
 
-func (parser *Parser) ParseApi() {
-	packages := parser.ScanPackages()
+// @Tag users Operations on user accounts
+func (parser *Parser) ParseTagComment(commentLine string) error {
+	fields := strings.Fields(commentLine)
+	if len(fields) == 0 {
+		return fmt.Errorf("Can not parse tag comment \"%s\", skipped.", commentLine)
+	}
+	if parser.Listing.TagDescriptions == nil {
+		parser.Listing.TagDescriptions = make(map[string]string)
+	}
+	parser.Listing.TagDescriptions[fields[0]] = strings.TrimSpace(commentLine[len(fields[0]):])
+	return nil
+}
+
+// @Environment staging https://staging.example.com/v1
+func (parser *Parser) ParseEnvironmentComment(commentLine string) error {
+	fields := strings.Fields(commentLine)
+	if len(fields) != 2 {
+		return fmt.Errorf("Can not parse environment comment \"%s\", skipped.", commentLine)
+	}
+	if parser.Listing.Environments == nil {
+		parser.Listing.Environments = make(map[string]string)
+	}
+	parser.Listing.Environments[fields[0]] = fields[1]
+	return nil
+}
+
+// ParseApi parses the API packages. If CollectErrors is set, parsing goes on after failures
+// and all of them are returned as ParseErrors
+func (parser *Parser) ParseApi() error {
+	packages, err := parser.ScanPackages()
+	if err != nil {
+		return err
+	}
 
 	for _, packageName := range packages {
-		parser.ParseTypeDefinitions(packageName)
+		if err := parser.ParseTypeDefinitions(packageName); err != nil {
+			return err
+		}
+	}
+
+	if parser.DiscoverRoutes {
+		for _, packageName := range packages {
+			if err := parser.ParseRoutes(packageName); err != nil {
+				return err
+			}
+		}
 	}
 
 	for _, packageName := range packages {
-		parser.ParseApiDescription(packageName)
+		if err := parser.ParseApiDescription(packageName); err != nil {
+			return err
+		}
+	}
+	if parser.SortOutput {
+		parser.sortApis()
+	}
+
+	if len(parser.Errors) > 0 {
+		return parser.Errors
+	}
+	return nil
+}
+
+// sortApis orders the resources and apis by path, and the operations of each api by http method
+func (parser *Parser) sortApis() {
+	sort.SliceStable(parser.Listing.Apis, func(i, j int) bool {
+		return parser.Listing.Apis[i].Path < parser.Listing.Apis[j].Path
+	})
+	for _, api := range parser.TopLevelApis {
+		sort.SliceStable(api.Apis, func(i, j int) bool {
+			return api.Apis[i].Path < api.Apis[j].Path
+		})
+		for _, subApi := range api.Apis {
+			sort.SliceStable(subApi.Operations, func(i, j int) bool {
+				return subApi.Operations[i].HttpMethod < subApi.Operations[j].HttpMethod
+			})
+		}
 	}
 }
 
-func (parser *Parser) ScanPackages() []string {
+func (parser *Parser) ScanPackages() ([]string, error) {
 	var res []string
 	existsPackages := make(map[string]bool)
 
@@ -316,13 +564,20 @@ func (parser *Parser) ScanPackages() []string {
 			res = append(res, packageName)
 
 			// get it's real path
-			pkgRealPath := parser.GetRealPackagePath(packageName)
+			pkgRealPath, err := parser.GetRealPackagePath(packageName)
+			if err != nil {
+				if err := parser.handleError(parser.newParseError(token.NoPos, packageName, "", err)); err != nil {
+					return nil, err
+				}
+				continue
+			}
 
-			// Then walk
+			// Then walk. Sub package names are built from the path relative to the package dir,
+			// because the package can live outside of GOPATH (module cache, replaced module)
 			var walker filepath.WalkFunc = func(path string, info os.FileInfo, err error) error {
-				if !isIgnorablePath(path, info) {
-					if idx := strings.Index(path, packageName); idx != -1 {
-						pack := path[idx:]
+				if err == nil && !isIgnorablePath(path, info) {
+					if rel, err := filepath.Rel(pkgRealPath, path); err == nil && rel != "." {
+						pack := packageName + "/" + filepath.ToSlash(rel)
 						if v, ok := existsPackages[pack]; !ok || v == false {
 							existsPackages[pack] = true
 							res = append(res, pack)
@@ -336,7 +591,7 @@ func (parser *Parser) ScanPackages() []string {
 		}
 	}
 
-	return res
+	return res, nil
 }
 
 func isIgnorablePath(path string, info os.FileInfo) bool {
@@ -359,83 +614,124 @@ func isIgnorablePath(path string, info os.FileInfo) bool {
 	return false
 }
 
-func (parser *Parser) ParseTypeDefinitions(packageName string) {
+func (parser *Parser) ParseTypeDefinitions(packageName string) error {
 	parser.CurrentPackage = packageName
 
-	pkgRealPath := parser.GetRealPackagePath(packageName)
+	pkgRealPath, err := parser.GetRealPackagePath(packageName)
+	if err != nil {
+		return parser.handleError(parser.newParseError(token.NoPos, packageName, "", err))
+	}
 	//	log.Printf("Parse type definition of %#v\n", packageName)
 
 	if _, ok := parser.TypeDefinitions[pkgRealPath]; !ok {
 		parser.TypeDefinitions[pkgRealPath] = make(map[string]*ast.TypeSpec)
 	}
+	parser.TypeConstants[pkgRealPath] = make(map[string][]*ast.Ident)
+	parser.SwaggerTypes[pkgRealPath] = make(map[string]string)
+	parser.DeprecatedTypes[pkgRealPath] = make(map[string]bool)
 
-	astPackages := parser.GetPackageAst(pkgRealPath)
-	for _, astPackage := range astPackages {
-		for _, astFile := range astPackage.Files {
-			for _, astDeclaration := range astFile.Decls {
-				if generalDeclaration, ok := astDeclaration.(*ast.GenDecl); ok && generalDeclaration.Tok == token.TYPE {
-					for _, astSpec := range generalDeclaration.Specs {
-						if typeSpec, ok := astSpec.(*ast.TypeSpec); ok {
-							parser.TypeDefinitions[pkgRealPath][typeSpec.Name.String()] = typeSpec
+	astPackages, err := parser.GetPackageAst(pkgRealPath)
+	if err != nil {
+		return parser.handleError(parser.newParseError(token.NoPos, packageName, "", err))
+	}
+	for _, astFile := range sortedFiles(astPackages) {
+		for _, astDeclaration := range astFile.Decls {
+			if generalDeclaration, ok := astDeclaration.(*ast.GenDecl); ok && generalDeclaration.Tok == token.TYPE {
+				for _, astSpec := range generalDeclaration.Specs {
+					if typeSpec, ok := astSpec.(*ast.TypeSpec); ok {
+						parser.TypeDefinitions[pkgRealPath][typeSpec.Name.String()] = typeSpec
+						// Comments of declarations without parentheses belong to the declaration
+						var doc *ast.CommentGroup
+						if !generalDeclaration.Lparen.IsValid() {
+							doc = generalDeclaration.Doc
+						}
+						parser.parseSwaggerTypeAnnotation(pkgRealPath, packageName, typeSpec, doc)
+						if isDeprecated(typeSpec.Doc) || isDeprecated(doc) {
+							parser.DeprecatedTypes[pkgRealPath][typeSpec.Name.String()] = true
 						}
 					}
 				}
+			} else if ok && generalDeclaration.Tok == token.CONST {
+				parser.parseConstDeclaration(pkgRealPath, generalDeclaration)
 			}
 		}
 	}
 
 	//log.Fatalf("Type definition parsed %#v\n", parser.ParseImportStatements(packageName))
 
-	for importedPackage, _ := range parser.ParseImportStatements(packageName) {
+	imports, err := parser.ParseImportStatements(packageName)
+	if err != nil {
+		return err
+	}
+	importedPackages := make([]string, 0, len(imports))
+	for importedPackage := range imports {
+		importedPackages = append(importedPackages, importedPackage)
+	}
+	sort.Strings(importedPackages)
+	for _, importedPackage := range importedPackages {
 		//log.Printf("Import: %v, %v\n", importedPackage, v)
-		parser.ParseTypeDefinitions(importedPackage)
+		if err := parser.ParseTypeDefinitions(importedPackage); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func (parser *Parser) ParseImportStatements(packageName string) map[string]bool {
+func (parser *Parser) ParseImportStatements(packageName string) (map[string]bool, error) {
 
 	parser.CurrentPackage = packageName
-	pkgRealPath := parser.GetRealPackagePath(packageName)
+	pkgRealPath, err := parser.GetRealPackagePath(packageName)
+	if err != nil {
+		return nil, parser.newParseError(token.NoPos, packageName, "", err)
+	}
 
 	imports := make(map[string]bool)
-	astPackages := parser.GetPackageAst(pkgRealPath)
+	astPackages, err := parser.GetPackageAst(pkgRealPath)
+	if err != nil {
+		return nil, parser.newParseError(token.NoPos, packageName, "", err)
+	}
 
 	parser.PackageImports[pkgRealPath] = make(map[string][]string)
-	for _, astPackage := range astPackages {
-		for _, astFile := range astPackage.Files {
-			for _, astImport := range astFile.Imports {
-				importedPackageName := strings.Trim(astImport.Path.Value, "\"")
-				if !parser.isIgnoredPackage(importedPackageName) {
-					realPath := parser.GetRealPackagePath(importedPackageName)
-					//log.Printf("path: %#v, original path: %#v", realPath, astImport.Path.Value)
-					if _, ok := parser.TypeDefinitions[realPath]; !ok {
-						imports[importedPackageName] = true
-						//log.Printf("Parse %s, Add new import definition:%s\n", packageName, astImport.Path.Value)
+	for _, astFile := range sortedFiles(astPackages) {
+		for _, astImport := range astFile.Imports {
+			importedPackageName := strings.Trim(astImport.Path.Value, "\"")
+			if !parser.isIgnoredPackage(importedPackageName) {
+				realPath, err := parser.GetRealPackagePath(importedPackageName)
+				if err != nil {
+					if err := parser.handleError(parser.newParseError(astImport.Pos(), packageName, "", err)); err != nil {
+						return nil, err
 					}
+					continue
+				}
+				//log.Printf("path: %#v, original path: %#v", realPath, astImport.Path.Value)
+				if _, ok := parser.TypeDefinitions[realPath]; !ok {
+					imports[importedPackageName] = true
+					//log.Printf("Parse %s, Add new import definition:%s\n", packageName, astImport.Path.Value)
+				}
 
-					var importedPackageAlias string
-					if astImport.Name != nil && astImport.Name.Name != "." && astImport.Name.Name != "_" {
-						importedPackageAlias = astImport.Name.Name
-					} else {
-						importPath := strings.Split(importedPackageName, "/")
-						importedPackageAlias = importPath[len(importPath)-1]
-					}
+				// Dot imports are kept under the "." alias, their types are used without qualifier
+				var importedPackageAlias string
+				if astImport.Name != nil && astImport.Name.Name != "_" {
+					importedPackageAlias = astImport.Name.Name
+				} else {
+					importPath := strings.Split(importedPackageName, "/")
+					importedPackageAlias = importPath[len(importPath)-1]
+				}
 
-					isExists := false
-					for _, v := range parser.PackageImports[pkgRealPath][importedPackageAlias] {
-						if v == importedPackageName {
-							isExists = true
-						}
+				isExists := false
+				for _, v := range parser.PackageImports[pkgRealPath][importedPackageAlias] {
+					if v == importedPackageName {
+						isExists = true
 					}
+				}
 
-					if !isExists {
-						parser.PackageImports[pkgRealPath][importedPackageAlias] = append(parser.PackageImports[pkgRealPath][importedPackageAlias], importedPackageName)
-					}
+				if !isExists {
+					parser.PackageImports[pkgRealPath][importedPackageAlias] = append(parser.PackageImports[pkgRealPath][importedPackageAlias], importedPackageName)
 				}
 			}
 		}
 	}
-	return imports
+	return imports, nil
 }
 
 func (parser *Parser) GetModelDefinition(model string, packageName string) *ast.TypeSpec {
@@ -452,17 +748,32 @@ func (parser *Parser) GetModelDefinition(model string, packageName string) *ast.
 	return astTypeSpec
 }
 
-func (parser *Parser) FindModelDefinition(modelName string, currentPackage string) (*ast.TypeSpec, string) {
+func (parser *Parser) FindModelDefinition(modelName string, currentPackage string) (*ast.TypeSpec, string, error) {
 	var model *ast.TypeSpec
 	var modelPackage string
 
 	modelNameParts := strings.Split(modelName, ".")
 
-	//if no dot in name - it can be only model from current package
-	if len(modelNameParts) == 1 {
+	//name qualified with import path, e.g. github.com/foo/bar.Model
+	if packagePath, typeName := splitQualifiedTypeName(modelName); packagePath != "" {
+		modelPackage = packagePath
+		if model = parser.GetModelDefinition(typeName, packagePath); model == nil {
+			return nil, "", fmt.Errorf("Can not find definition of %s model in package %s", typeName, packagePath)
+		}
+	} else if len(modelNameParts) == 1 {
+		//if no dot in name - it can be only model from current package or dot imported package
 		modelPackage = currentPackage
 		if model = parser.GetModelDefinition(modelName, currentPackage); model == nil {
-			log.Fatalf("Can not find definition of %s model. Current package %s", modelName, currentPackage)
+			pkgRealPath := parser.CheckRealPackagePath(currentPackage)
+			for _, packageName := range parser.PackageImports[pkgRealPath]["."] {
+				if model = parser.GetModelDefinition(modelName, packageName); model != nil {
+					modelPackage = packageName
+					break
+				}
+			}
+		}
+		if model == nil {
+			return nil, "", fmt.Errorf("Can not find definition of %s model. Current package %s", modelName, currentPackage)
 		}
 	} else {
 		//first try to assume what name is absolute
@@ -474,15 +785,15 @@ func (parser *Parser) FindModelDefinition(modelName string, currentPackage strin
 
 			//can not get model by absolute name.
 			if len(modelNameParts) > 2 {
-				log.Fatalf("Can not find definition of %s model. Name looks like absolute, but model not found in %s package", modelNameFromPath, absolutePackageName)
+				return nil, "", fmt.Errorf("Can not find definition of %s model. Name looks like absolute, but model not found in %s package", modelNameFromPath, absolutePackageName)
 			}
 
 			// lets try to find it in imported packages
 			pkgRealPath := parser.CheckRealPackagePath(currentPackage)
 			if imports, ok := parser.PackageImports[pkgRealPath]; !ok {
-				log.Fatalf("Can not find definition of %s model. Package %s dont import anything", modelNameFromPath, pkgRealPath)
+				return nil, "", fmt.Errorf("Can not find definition of %s model. Package %s dont import anything", modelNameFromPath, pkgRealPath)
 			} else if relativePackage, ok := imports[modelNameParts[0]]; !ok {
-				log.Fatalf("Package %s is not imported to %s, Imported: %#v\n", modelNameParts[0], currentPackage, imports)
+				return nil, "", fmt.Errorf("Package %s is not imported to %s, Imported: %#v", modelNameParts[0], currentPackage, imports)
 			} else {
 				var modelFound bool
 
@@ -496,60 +807,90 @@ func (parser *Parser) FindModelDefinition(modelName string, currentPackage strin
 				}
 
 				if !modelFound {
-					log.Fatalf("Can not find definition of %s model in package %s", modelNameFromPath, relativePackage)
+					return nil, "", fmt.Errorf("Can not find definition of %s model in package %s", modelNameFromPath, relativePackage)
 				}
 			}
 		}
 	}
-	return model, modelPackage
+	return model, modelPackage, nil
 }
 
-func (parser *Parser) ParseApiDescription(packageName string) {
+// ParseApiDescription parses the operations of the package. Annotations which do not match
+// their syntax are skipped with a warning, failures to resolve the types they reference are errors
+func (parser *Parser) ParseApiDescription(packageName string) error {
 	parser.CurrentPackage = packageName
-	pkgRealPath := parser.GetRealPackagePath(packageName)
-
-	astPackages := parser.GetPackageAst(pkgRealPath)
-	for _, astPackage := range astPackages {
-		for _, astFile := range astPackage.Files {
-			for _, astDescription := range astFile.Decls {
-				switch astDeclaration := astDescription.(type) {
-				case *ast.FuncDecl:
-					if parser.IsController(astDeclaration, parser.ControllerClass) {
-						operation := NewOperation(parser, packageName)
-						if astDeclaration.Doc != nil && astDeclaration.Doc.List != nil {
-							for _, comment := range astDeclaration.Doc.List {
-								if err := operation.ParseComment(comment.Text); err != nil {
-									log.Printf("Can not parse comment for function: %v, package: %v, got error: %v\n", astDeclaration.Name.String(), packageName, err)
+	pkgRealPath, err := parser.GetRealPackagePath(packageName)
+	if err != nil {
+		return parser.handleError(parser.newParseError(token.NoPos, packageName, "", err))
+	}
+
+	astPackages, err := parser.GetPackageAst(pkgRealPath)
+	if err != nil {
+		return parser.handleError(parser.newParseError(token.NoPos, packageName, "", err))
+	}
+	for _, astFile := range sortedFiles(astPackages) {
+		for _, astDescription := range astFile.Decls {
+			switch astDeclaration := astDescription.(type) {
+			case *ast.FuncDecl:
+				if parser.IsController(astDeclaration, parser.ControllerClass) {
+					operation := NewOperation(parser, packageName)
+					operation.Deprecated = isDeprecated(astDeclaration.Doc)
+					if astDeclaration.Doc != nil && astDeclaration.Doc.List != nil {
+						for _, comment := range astDeclaration.Doc.List {
+							annotation := strings.TrimSpace(strings.TrimLeft(comment.Text, "/"))
+							if err := operation.ParseComment(comment.Text); err != nil {
+								var parseError *ParseError
+								if !errors.As(err, &parseError) {
+									parser.warn(comment.Pos(), packageName, annotation, err)
+									continue
+								}
+								if err := parser.handleError(parser.newParseError(comment.Pos(), packageName, annotation, err)); err != nil {
+									return err
 								}
 							}
 						}
-						if operation.Path != "" {
-							parser.AddOperation(operation)
-						}
+					}
+					if parser.DiscoverRoutes && astDeclaration.Doc != nil {
+						parser.bindRoute(operation, astDeclaration)
+					}
+					if operation.Path != "" {
+						parser.checkOperation(operation, astDeclaration.Doc)
+						parser.AddOperation(operation)
 					}
 				}
 			}
-			for _, astComment := range astFile.Comments {
-				for _, commentLine := range strings.Split(astComment.Text(), "\n") {
-					parser.ParseSubApiDescription(commentLine)
+		}
+		for _, astComment := range astFile.Comments {
+			for _, comment := range astComment.List {
+				offset := 0
+				for _, commentLine := range strings.Split(comment.Text, "\n") {
+					pos := comment.Pos() + token.Pos(offset)
+					offset += len(commentLine) + 1
+
+					commentLine = strings.TrimPrefix(strings.TrimSpace(commentLine), "//")
+					commentLine = strings.TrimSuffix(strings.TrimPrefix(commentLine, "/*"), "*/")
+					if err := parser.ParseSubApiDescription(strings.TrimSpace(commentLine)); err != nil {
+						parser.warn(pos, packageName, strings.TrimSpace(commentLine), err)
+					}
 				}
 			}
 		}
 	}
+	return nil
 }
 
 // Parse sub api declaration
 // @SubApi Very fancy API [/fancy-api]
-func (parser *Parser) ParseSubApiDescription(commentLine string) {
+func (parser *Parser) ParseSubApiDescription(commentLine string) error {
 	if !strings.HasPrefix(commentLine, "@SubApi") {
-		return
+		return nil
 	} else {
 		commentLine = strings.TrimSpace(commentLine[len("@SubApi"):])
 	}
 	re := regexp.MustCompile(`([^\[]+)\[{1}([\w\_\-/]+)`)
 
 	if matches := re.FindStringSubmatch(commentLine); len(matches) != 3 {
-		log.Printf("Can not parse sub api description %s, skipped", commentLine)
+		return fmt.Errorf("Can not parse sub api description \"%s\", skipped.", commentLine)
 	} else {
 		found := false
 		for _, ref := range parser.Listing.Apis {
@@ -565,14 +906,13 @@ func (parser *Parser) ParseSubApiDescription(commentLine string) {
 			parser.Listing.Apis = append(parser.Listing.Apis, subApi)
 		}
 	}
+	return nil
 }
 
 func (parser *Parser) isIgnoredPackage(packageName string) bool {
 	r, _ := regexp.Compile("appengine+")
-	matched, err := regexp.MatchString(parser.Ignore, packageName)
-	if err != nil {
-		log.Fatalf("The -ignore argument is not a valid regular expression: %v\n", err)
-	}
+	// parser.Ignore is validated by NewParser
+	matched, _ := regexp.MatchString(parser.Ignore, packageName)
 	return packageName == "C" || r.MatchString(packageName) || matched
 }
 
@@ -3,7 +3,7 @@ package parser_test
 import (
 	"fmt"
 	"go/ast"
-
+	"io/ioutil"
 	"os"
 	"path"
 	"strings"
@@ -49,8 +49,10 @@ func (suite *ParserSuite) SetupSuite() {
 			suite.T().Fatalf("Please, set $GOPATH environment variable\n")
 		}
 
-		initialisedParser2.ParseGeneralApiInfo(path.Join(gopath, "src", "github.com/yvasiyarov/swagger/example/web/main.go"))
-		initialisedParser2.ParseApi()
+		err = initialisedParser2.ParseGeneralApiInfo(path.Join(gopath, "src", "github.com/yvasiyarov/swagger/example/web/main.go"))
+		assert.NoError(suite.T(), err, "Can not parse general API information")
+		err = initialisedParser2.ParseApi()
+		assert.NoError(suite.T(), err, "Can not parse API")
 	}
 	suite.parser = initialisedParser2
 }
@@ -59,6 +61,56 @@ func (suite *ParserSuite) TestNewParser() {
 	assert.NotNil(suite.T(), suite.parser, "Parser instance was not created")
 }
 
+func (suite *ParserSuite) TestSecurityDefinitions() {
+	authorizations := suite.parser.Listing.Authorizations
+	assert.Len(suite.T(), authorizations, 2, "Security definitions not parsed")
+	if apiKey, ok := authorizations["ApiKeyAuth"]; assert.True(suite.T(), ok, "API key definition not parsed") {
+		assert.Equal(suite.T(), &parser.SecurityDefinition{Type: "apiKey", PassAs: "header", Keyname: "Authorization"}, apiKey, "API key definition not parsed")
+	}
+	if oauth, ok := authorizations["OAuth2"]; assert.True(suite.T(), ok, "OAuth2 definition not parsed") {
+		assert.Equal(suite.T(), "oauth2", oauth.Type, "OAuth2 definition not parsed")
+		assert.Equal(suite.T(), "accessCode", oauth.Flow, "OAuth2 flow not parsed")
+		assert.Equal(suite.T(), []parser.Scope{{"read", "Grants read access"}, {"write", "Grants write access"}}, oauth.Scopes, "Scopes not parsed")
+		assert.Equal(suite.T(), "http://yvasiyarov.com/oauth/token", oauth.GrantTypes["authorization_code"].TokenEndpoint.Url, "Grant type not set")
+		assert.Equal(suite.T(), "http://yvasiyarov.com/oauth/authorize", oauth.GrantTypes["authorization_code"].TokenRequestEndpoint.Url, "Grant type not set")
+	}
+	assert.Empty(suite.T(), suite.parser.Warnings, "Security annotations reported as problems")
+}
+
+func (suite *ParserSuite) TestApiKeyWithoutName() {
+	dir, err := ioutil.TempDir("", "swagger-security")
+	assert.NoError(suite.T(), err, "Can not create temp dir")
+	defer os.RemoveAll(dir)
+
+	mainApiFile := path.Join(dir, "main.go")
+	assert.NoError(suite.T(), ioutil.WriteFile(mainApiFile, []byte(`package main
+
+// @SecurityDefinitions.apikey ApiKeyAuth
+// @In header
+// @SecurityDefinitions.apikey TokenAuth
+// @In query
+// @Name token
+func main() {}
+`), 0666), "Can not write file")
+
+	p := &parser.Parser{Listing: &parser.ResourceListing{}}
+	assert.NoError(suite.T(), p.ParseGeneralApiInfo(mainApiFile), "Can not parse general API info")
+	assert.NotContains(suite.T(), p.Listing.Authorizations, "ApiKeyAuth", "API key without @Name must be skipped")
+	assert.Equal(suite.T(), &parser.SecurityDefinition{Type: "apiKey", PassAs: "query", Keyname: "token"}, p.Listing.Authorizations["TokenAuth"], "API key not parsed")
+	if assert.Len(suite.T(), p.Warnings, 1, "API key without @Name not reported") {
+		assert.Equal(suite.T(), "@SecurityDefinitions.apikey ApiKeyAuth", p.Warnings[0].Annotation, "Annotation not set")
+		assert.EqualError(suite.T(), p.Warnings[0].Err, "@Name of API key ApiKeyAuth is missing, skipped.", "API key without @Name not reported")
+	}
+}
+
+func (suite *ParserSuite) TestEnvironments() {
+	assert.Equal(suite.T(), map[string]string{
+		"dev":  "http://127.0.0.1:3000/",
+		"prod": "https://api.yvasiyarov.com/",
+	}, suite.parser.Listing.Environments, "Environments not parsed")
+	assert.Error(suite.T(), suite.parser.ParseEnvironmentComment("staging"), "Environment without base path must fail")
+}
+
 func (suite *ParserSuite) TestTopLevelAPI() {
 	assert.Len(suite.T(), suite.parser.TopLevelApis, 1, "Top level API not parsed")
 	if topApi, ok := suite.parser.TopLevelApis["testapi"]; !ok {
@@ -164,6 +216,10 @@ func (suite *ParserSuite) CheckGetStructByInt(op *parser.Operation) {
 
 	assert.Len(suite.T(), op.Parameters, 3, "Params not parsed")
 	assert.Len(suite.T(), op.ResponseMessages, 3, "Response message not parsed")
+	assert.Equal(suite.T(), map[string][]parser.Scope{
+		"OAuth2":     {{Scope: "read"}, {Scope: "write"}},
+		"ApiKeyAuth": {},
+	}, op.Security, "Security not parsed")
 
 	assert.Len(suite.T(), op.Models, 2, "Models not parsed %#v", op.Models)
 }
@@ -218,6 +274,10 @@ func (suite *ParserSuite) CheckGetStructArrayByString(op *parser.Operation) {
 	assert.Len(suite.T(), op.Parameters, 3, "Params not parsed")
 	assert.Len(suite.T(), op.ResponseMessages, 3, "Response message not parsed")
 
+	assert.Equal(suite.T(), "10", op.Parameters[2].DefaultValue, "Default value not parsed")
+	assert.Equal(suite.T(), "20", op.Parameters[2].Example, "Param example not parsed")
+	assert.Contains(suite.T(), op.ResponseMessages[0].Examples[parser.ContentTypeJson], `{"id": 42, "Name": "First"}`, "Example file not loaded")
+
 	assert.Len(suite.T(), op.Models, 2, "Models not parsed %#v", op.Models)
 }
 
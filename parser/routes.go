@@ -0,0 +1,407 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Route is a path and http method bound to a handler function by router registration code
+type Route struct {
+	Method string // empty if the router accepts any method
+	Path   string
+	Pos    token.Pos
+}
+
+func (route *Route) String() string {
+	if route.Method == "" {
+		return route.Path
+	}
+	return fmt.Sprintf("%s [%s]", route.Path, strings.ToLower(route.Method))
+}
+
+var httpMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "DELETE": true, "PATCH": true, "HEAD": true, "OPTIONS": true,
+}
+
+var (
+	// :id and *filepath of gin, echo and httprouter
+	colonPathParam = regexp.MustCompile(`/[:*](\w+)`)
+	// {id:[0-9]+} of gorilla/mux and chi, {path...} of net/http
+	patternPathParam = regexp.MustCompile(`\{(\w+)(?::[^}]*|\.\.\.)\}`)
+)
+
+// routeCollector finds the routes registered in the functions of a package
+type routeCollector struct {
+	parser      *Parser
+	packageName string
+	info        *types.Info
+	// Import paths by the name of the imported package in the current file
+	imports map[string]string
+	// Path prefixes of router groups by variable name, in the current function
+	prefixes map[string]string
+	// Calls which are part of a route registered already: router.HandleFunc(...).Methods("GET")
+	handled map[*ast.CallExpr]bool
+}
+
+// ParseRoutes binds handler functions to the routes registered in the package by calls to common routers:
+// net/http, gocraft/web, gorilla/mux, chi, gin and echo
+func (parser *Parser) ParseRoutes(packageName string) error {
+	pkgRealPath, err := parser.GetRealPackagePath(packageName)
+	if err != nil {
+		return parser.handleError(parser.newParseError(token.NoPos, packageName, "", err))
+	}
+	astPackages, err := parser.GetPackageAst(pkgRealPath)
+	if err != nil {
+		return parser.handleError(parser.newParseError(token.NoPos, packageName, "", err))
+	}
+
+	if parser.Routes == nil {
+		parser.Routes = make(map[string][]*Route)
+	}
+	collector := &routeCollector{
+		parser:      parser,
+		packageName: packageName,
+		info:        parser.TypesInfo(packageName),
+		handled:     make(map[*ast.CallExpr]bool),
+	}
+	for _, astFile := range sortedFiles(astPackages) {
+		collector.imports = fileImports(astFile)
+		for _, astDeclaration := range astFile.Decls {
+			if funcDeclaration, ok := astDeclaration.(*ast.FuncDecl); ok && funcDeclaration.Body != nil {
+				collector.prefixes = make(map[string]string)
+				collector.collect(funcDeclaration.Body)
+			}
+		}
+	}
+	return nil
+}
+
+// bindRoute sets the path and method of the operation from the route registered for its handler.
+// @Router is kept if no route is found, otherwise it must match the route
+func (parser *Parser) bindRoute(operation *Operation, funcDeclaration *ast.FuncDecl) {
+	routes := parser.Routes[funcKey(operation.packageName, funcDeclaration)]
+	if len(routes) == 0 {
+		return
+	}
+	route := routes[0]
+	for _, other := range routes[1:] {
+		parser.warn(other.Pos, operation.packageName, "", fmt.Errorf("%s is registered for several routes, only %s is documented", funcDeclaration.Name.Name, route))
+	}
+
+	if operation.Path == "" {
+		if route.Method == "" {
+			parser.warn(route.Pos, operation.packageName, "", fmt.Errorf("Route %s of %s accepts any method, add @Router to document it", route.Path, funcDeclaration.Name.Name))
+			return
+		}
+		operation.Path, operation.HttpMethod = route.Path, route.Method
+		return
+	}
+
+	method := route.Method
+	if method == "" {
+		method = operation.HttpMethod
+	}
+	if strings.TrimPrefix(operation.Path, "/") != strings.TrimPrefix(route.Path, "/") || method != operation.HttpMethod {
+		pos, annotation := findAnnotation(funcDeclaration.Doc, "@router", "")
+		parser.warn(pos, operation.packageName, annotation, fmt.Errorf("@Router %s [%s] does not match the route %s [%s] registered at %s",
+			operation.Path, strings.ToLower(operation.HttpMethod), route.Path, strings.ToLower(method), parser.Position(route.Pos)))
+		operation.Path, operation.HttpMethod = route.Path, method
+	}
+}
+
+func (c *routeCollector) collect(body *ast.BlockStmt) {
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.AssignStmt:
+			// v1 := router.Group("/v1")
+			for i, value := range n.Rhs {
+				if prefix, ok := c.prefix(value); ok && i < len(n.Lhs) {
+					if ident, ok := n.Lhs[i].(*ast.Ident); ok {
+						c.prefixes[ident.Name] = prefix
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, value := range n.Values {
+				if prefix, ok := c.prefix(value); ok && i < len(n.Names) {
+					c.prefixes[n.Names[i].Name] = prefix
+				}
+			}
+		case *ast.CallExpr:
+			c.collectCall(n)
+		}
+		return true
+	})
+}
+
+func (c *routeCollector) collectCall(call *ast.CallExpr) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || c.handled[call] {
+		return
+	}
+
+	switch selector.Sel.Name {
+	case "Methods":
+		// gorilla/mux: router.HandleFunc("/users", ListUsers).Methods("GET")
+		if route, ok := selector.X.(*ast.CallExpr); ok {
+			var methods []string
+			for _, arg := range call.Args {
+				if method, ok := c.stringValue(arg); ok {
+					methods = append(methods, strings.ToUpper(method))
+				}
+			}
+			c.handled[route] = true
+			c.addRoutes(route, methods)
+		}
+	case "Route", "Group":
+		// chi: router.Route("/users", func(r chi.Router) { ... }), the routes of the function get the prefix
+		prefix, _ := c.prefix(selector.X)
+		for _, arg := range call.Args {
+			if path, ok := c.stringValue(arg); ok {
+				prefix = joinRoutePath(prefix, path)
+			} else if function, ok := arg.(*ast.FuncLit); ok && len(function.Type.Params.List) > 0 {
+				for _, name := range function.Type.Params.List[0].Names {
+					c.prefixes[name.Name] = prefix
+				}
+			}
+		}
+	default:
+		c.addRoutes(call, nil)
+	}
+}
+
+// addRoutes records the route registered by the call, if it is one. The methods of gorilla/mux routes are declared separately
+func (c *routeCollector) addRoutes(call *ast.CallExpr, methods []string) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+
+	args := call.Args
+	method := ""
+	switch name := selector.Sel.Name; {
+	case httpMethods[strings.ToUpper(name)]:
+		// gocraft/web and chi: Get, gin and echo: GET
+		method = strings.ToUpper(name)
+	case name == "Handle" || name == "Method" || name == "MethodFunc" || name == "Add":
+		// gin: Handle("GET", "/users", ListUsers), chi: Method, echo: Add
+		if len(args) > 2 {
+			if value, ok := c.stringValue(args[0]); ok && httpMethods[strings.ToUpper(value)] {
+				method, args = strings.ToUpper(value), args[1:]
+			}
+		}
+		if method == "" && name != "Handle" {
+			return
+		}
+	case name == "HandleFunc" || name == "Any":
+	default:
+		return
+	}
+	if len(args) < 2 {
+		return
+	}
+
+	routePath, ok := c.stringValue(args[0])
+	if !ok {
+		return
+	}
+	if fields := strings.Fields(routePath); len(fields) == 2 && httpMethods[fields[0]] {
+		// net/http patterns: "GET /users/{id}"
+		method, routePath = fields[0], fields[1]
+	}
+	prefix, _ := c.prefix(selector.X)
+	routePath = joinRoutePath(prefix, routePath)
+	if !strings.HasPrefix(routePath, "/") {
+		// Not a route, e.g. client.Get(url, ...)
+		return
+	}
+	routePath = patternPathParam.ReplaceAllString(colonPathParam.ReplaceAllString(routePath, "/{$1}"), "{$1}")
+
+	if len(methods) == 0 {
+		methods = []string{method}
+	}
+	// Handlers can be wrapped in middlewares, every function is bound to the route
+	for _, arg := range args[1:] {
+		key := c.handlerKey(arg)
+		if key == "" {
+			continue
+		}
+		for _, method := range methods {
+			c.parser.Routes[key] = append(c.parser.Routes[key], &Route{Method: method, Path: routePath, Pos: call.Pos()})
+		}
+	}
+}
+
+// prefix returns the path prefix of a router expression. Groups and sub routers add their path to the prefix of their parent
+func (c *routeCollector) prefix(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		prefix, ok := c.prefixes[e.Name]
+		return prefix, ok
+	case *ast.ParenExpr:
+		return c.prefix(e.X)
+	case *ast.CallExpr:
+		selector, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return "", false
+		}
+		parent, ok := c.prefix(selector.X)
+		switch selector.Sel.Name {
+		case "Group", "PathPrefix", "Subrouter":
+			// gin and echo: Group("/v1"), gorilla/mux: PathPrefix("/v1").Subrouter(), gocraft/web: Subrouter(Context{}, "/v1")
+			for _, arg := range e.Args {
+				if path, ok := c.stringValue(arg); ok {
+					return joinRoutePath(parent, path), true
+				}
+			}
+			return parent, true
+		}
+		// Configuration of the parent: router.Use(middleware)
+		return parent, ok
+	}
+	return "", false
+}
+
+// handlerKey returns the key of the handler function the expression refers to, the same as funcKey of its declaration
+func (c *routeCollector) handlerKey(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return c.handlerKey(e.X)
+	case *ast.CallExpr:
+		// Conversions and adapters: http.HandlerFunc(ListUsers)
+		if len(e.Args) == 1 {
+			return c.handlerKey(e.Args[0])
+		}
+	case *ast.Ident:
+		if c.info != nil {
+			if object := c.info.Uses[e]; object != nil {
+				function, _ := object.(*types.Func)
+				return typesFuncKey(function)
+			}
+		}
+		return c.packageName + "." + e.Name
+	case *ast.SelectorExpr:
+		if c.info != nil {
+			if function, ok := c.info.Uses[e.Sel].(*types.Func); ok {
+				return typesFuncKey(function)
+			}
+		}
+		// Method expressions: (*Context).ListUsers, Context.ListUsers, or functions of other packages: api.ListUsers
+		receiver := e.X
+		if paren, ok := receiver.(*ast.ParenExpr); ok {
+			receiver = paren.X
+		}
+		if star, ok := receiver.(*ast.StarExpr); ok {
+			receiver = star.X
+		}
+		switch r := receiver.(type) {
+		case *ast.Ident:
+			if importPath, ok := c.imports[r.Name]; ok {
+				return importPath + "." + e.Sel.Name
+			}
+			return c.packageName + "." + r.Name + "." + e.Sel.Name
+		case *ast.SelectorExpr:
+			if ident, ok := r.X.(*ast.Ident); ok {
+				if importPath, ok := c.imports[ident.Name]; ok {
+					return importPath + "." + r.Sel.Name + "." + e.Sel.Name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// stringValue returns the value of string literals and constants
+func (c *routeCollector) stringValue(expr ast.Expr) (string, bool) {
+	if c.info != nil {
+		if typeAndValue, ok := c.info.Types[expr]; ok && typeAndValue.Value != nil && typeAndValue.Value.Kind() == constant.String {
+			return constant.StringVal(typeAndValue.Value), true
+		}
+	}
+	if literal, ok := expr.(*ast.BasicLit); ok && literal.Kind == token.STRING {
+		if value, err := strconv.Unquote(literal.Value); err == nil {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// funcKey returns the import path of the package, the receiver type and the name of a function: github.com/foo/api.Context.ListUsers
+func funcKey(packageName string, funcDeclaration *ast.FuncDecl) string {
+	key := packageName + "."
+	if funcDeclaration.Recv != nil && len(funcDeclaration.Recv.List) > 0 {
+		receiver := funcDeclaration.Recv.List[0].Type
+		if star, ok := receiver.(*ast.StarExpr); ok {
+			receiver = star.X
+		}
+		switch r := receiver.(type) {
+		case *ast.IndexExpr:
+			receiver = r.X
+		case *ast.IndexListExpr:
+			receiver = r.X
+		}
+		if ident, ok := receiver.(*ast.Ident); ok {
+			key += ident.Name + "."
+		}
+	}
+	return key + funcDeclaration.Name.Name
+}
+
+// typesFuncKey returns the funcKey of a type checked function, or an empty string if the object is not a function
+func typesFuncKey(function *types.Func) string {
+	if function == nil || function.Pkg() == nil {
+		return ""
+	}
+	key := function.Pkg().Path() + "."
+	if signature, ok := function.Type().(*types.Signature); ok && signature.Recv() != nil {
+		receiver := signature.Recv().Type()
+		if pointer, ok := receiver.(*types.Pointer); ok {
+			receiver = pointer.Elem()
+		}
+		if named, ok := receiver.(*types.Named); ok {
+			key += named.Obj().Name() + "."
+		}
+	}
+	return key + function.Name()
+}
+
+// fileImports returns the import paths of a file by the name the packages are used with
+func fileImports(astFile *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, astImport := range astFile.Imports {
+		importPath := strings.Trim(astImport.Path.Value, "\"")
+		name := path.Base(importPath)
+		if isMajorVersion(name) {
+			// github.com/go-chi/chi/v5
+			name = path.Base(path.Dir(importPath))
+		}
+		if astImport.Name != nil {
+			name = astImport.Name.Name
+		}
+		imports[name] = importPath
+	}
+	return imports
+}
+
+func isMajorVersion(element string) bool {
+	_, err := strconv.Atoi(strings.TrimPrefix(element, "v"))
+	return strings.HasPrefix(element, "v") && err == nil
+}
+
+// joinRoutePath joins the prefix of a router group and the path of a route
+func joinRoutePath(prefix, routePath string) string {
+	if prefix == "" {
+		return routePath
+	}
+	if routePath == "" {
+		return prefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(routePath, "/")
+}
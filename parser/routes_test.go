@@ -0,0 +1,132 @@
+package parser_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/yvasiyarov/swagger/parser"
+)
+
+type RoutesSuite struct {
+	suite.Suite
+	root   string
+	parser *parser.Parser
+}
+
+const routesSource = `package api
+
+import "net/http"
+
+const usersPath = "/users"
+
+type Router struct{}
+
+func (r *Router) Group(prefix string) *Router                         { return r }
+func (r *Router) GET(path string, handlers ...interface{})            {}
+func (r *Router) Post(path string, handler interface{})               {}
+func (r *Router) HandleFunc(path string, handler interface{}) *Router { return r }
+func (r *Router) Methods(methods ...string) *Router                   { return r }
+func (r *Router) Route(prefix string, routes func(r *Router))         {}
+
+type Users struct{}
+
+func InitRouter(router *Router, users *Users) {
+	v1 := router.Group("/v1")
+	v1.GET(usersPath+"/:id", auth, users.GetUser)
+	router.Route("/v2", func(r *Router) {
+		r.Post(usersPath, (*Users).CreateUser)
+	})
+	router.HandleFunc("/users/{id:[0-9]+}", DeleteUser).Methods("DELETE")
+	http.HandleFunc("GET /health", Health)
+	http.HandleFunc("/old", ListUsers)
+}
+
+func auth() {}
+
+// @Title getUser
+// @Param id path int true "User ID"
+func (u *Users) GetUser() {}
+
+// @Title createUser
+// @Router /v2/users [post]
+func (u *Users) CreateUser() {}
+
+// @Title deleteUser
+// @Param id path int true "User ID"
+// @Router /users/{id} [get]
+func DeleteUser(w http.ResponseWriter, r *http.Request) {}
+
+// @Title health
+func Health(w http.ResponseWriter, r *http.Request) {}
+
+// @Title listUsers
+// @Router /users [get]
+func ListUsers(w http.ResponseWriter, r *http.Request) {}
+
+// @Title notRouted
+// @Router /not-routed [get]
+func NotRouted() {}
+`
+
+func (suite *RoutesSuite) SetupSuite() {
+	var err error
+	suite.root, err = ioutil.TempDir("", "swagger-routes")
+	assert.NoError(suite.T(), err, "Can not create temp dir")
+
+	assert.NoError(suite.T(), os.MkdirAll(filepath.Join(suite.root, "api"), 0777), "Can not create dir")
+	assert.NoError(suite.T(), ioutil.WriteFile(filepath.Join(suite.root, "go.mod"), []byte("module example.com/routes\n"), 0666), "Can not write file")
+	assert.NoError(suite.T(), ioutil.WriteFile(filepath.Join(suite.root, "api", "api.go"), []byte(routesSource), 0666), "Can not write file")
+
+	suite.parser, err = parser.NewParser(filepath.Join(suite.root, "api"), "", "^$", "", false)
+	assert.NoError(suite.T(), err, "Unable to complete suite initialization")
+	suite.parser.DiscoverRoutes = true
+	assert.NoError(suite.T(), suite.parser.ParseApi(), "Can not parse API")
+}
+
+func (suite *RoutesSuite) TearDownSuite() {
+	os.RemoveAll(suite.root)
+}
+
+func (suite *RoutesSuite) TestRoutes() {
+	routes := make(map[string]string)
+	for _, api := range suite.parser.TopLevelApis {
+		for _, subApi := range api.Apis {
+			for _, op := range subApi.Operations {
+				routes[op.Nickname] = op.HttpMethod + " " + op.Path
+			}
+		}
+	}
+	assert.Equal(suite.T(), map[string]string{
+		"getUser":    "GET /v1/users/{id}",
+		"createUser": "POST /v2/users",
+		"deleteUser": "DELETE /users/{id}",
+		"health":     "GET /health",
+		"listUsers":  "GET /old",
+		"notRouted":  "GET /not-routed",
+	}, routes, "Routes not bound to their handlers")
+}
+
+func (suite *RoutesSuite) TestMismatches() {
+	type warning struct {
+		Line    int
+		Message string
+	}
+	warnings := make([]warning, 0, len(suite.parser.Warnings))
+	for _, w := range suite.parser.Warnings {
+		warnings = append(warnings, warning{w.Line, w.Err.Error()})
+	}
+
+	fileName := filepath.Join(suite.root, "api", "api.go")
+	assert.ElementsMatch(suite.T(), []warning{
+		{41, "@Router /users/{id} [get] does not match the route /users/{id} [delete] registered at " + fileName + ":24"},
+		{48, "@Router /users [get] does not match the route /old [get] registered at " + fileName + ":26"},
+	}, warnings, "Mismatches of @Router and routes not reported")
+}
+
+func TestRoutesSuite(t *testing.T) {
+	suite.Run(t, &RoutesSuite{})
+}
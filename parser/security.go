@@ -0,0 +1,150 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OAuth2 flows of @SecurityDefinitions.oauth2.<flow>, as Swagger 2.0 names them
+var oauth2Flows = map[string]string{
+	"implicit":    "implicit",
+	"password":    "password",
+	"application": "application",
+	"accesscode":  "accessCode",
+}
+
+// ParseSecurityDefinitionComment parses the security definitions of the general API info.
+// Attributes which follow a definition belong to it:
+//
+//	@SecurityDefinitions.apikey ApiKeyAuth
+//	@In header
+//	@Name Authorization
+//	@SecurityDefinitions.oauth2.accessCode OAuth2
+//	@AuthorizationUrl https://example.com/oauth/authorize
+//	@TokenUrl https://example.com/oauth/token
+//	@Scope.write Grants write access
+//
+// It returns the definition the next attributes belong to
+func (parser *Parser) ParseSecurityDefinitionComment(current *SecurityDefinition, commentLine string) (*SecurityDefinition, error) {
+	fields := strings.Fields(commentLine)
+	if len(fields) == 0 {
+		return current, nil
+	}
+	attribute := strings.ToLower(fields[0])
+	value := strings.TrimSpace(commentLine[len(fields[0]):])
+
+	if strings.HasPrefix(attribute, "@securitydefinitions.") {
+		if value == "" {
+			return nil, fmt.Errorf("Name of security definition is missing, skipped.")
+		}
+		authorization := &SecurityDefinition{}
+		switch kind := attribute[len("@securitydefinitions."):]; {
+		case kind == "basic":
+			authorization.Type = "basicAuth"
+		case kind == "apikey":
+			authorization.Type, authorization.PassAs = "apiKey", "header"
+		case strings.HasPrefix(kind, "oauth2."):
+			flow, ok := oauth2Flows[kind[len("oauth2."):]]
+			if !ok {
+				return nil, fmt.Errorf("Unknown OAuth2 flow %s, skipped.", fields[0][len("@securitydefinitions.oauth2."):])
+			}
+			authorization.Type, authorization.Flow = "oauth2", flow
+		default:
+			return nil, fmt.Errorf("Unknown security definition %s, skipped.", fields[0])
+		}
+
+		if parser.Listing.Authorizations == nil {
+			parser.Listing.Authorizations = make(map[string]*SecurityDefinition)
+		}
+		parser.Listing.Authorizations[value] = authorization
+		return authorization, nil
+	}
+
+	switch {
+	case attribute == "@in", attribute == "@name", attribute == "@authorizationurl", attribute == "@tokenurl", strings.HasPrefix(attribute, "@scope."):
+		if current == nil {
+			return nil, fmt.Errorf("%s must follow @SecurityDefinitions, skipped.", fields[0])
+		}
+	default:
+		return current, nil
+	}
+
+	switch attribute {
+	case "@in":
+		if value != "header" && value != "query" {
+			return current, fmt.Errorf("API key must be passed in header or query, skipped.")
+		}
+		current.PassAs = value
+	case "@name":
+		current.Keyname = value
+	case "@authorizationurl":
+		current.AuthorizationUrl = value
+	case "@tokenurl":
+		current.TokenUrl = value
+	default:
+		current.Scopes = append(current.Scopes, Scope{Scope: fields[0][len("@scope."):], Description: value})
+	}
+	current.setGrantTypes()
+	return current, nil
+}
+
+// checkSecurityDefinitions skips the API key definitions without @Name, the key can not be sent without it.
+// annotations are the @SecurityDefinitions comments of the definitions
+func (parser *Parser) checkSecurityDefinitions(mainApiFile string, annotations map[*SecurityDefinition]string) {
+	names := make([]string, 0, len(parser.Listing.Authorizations))
+	for name := range parser.Listing.Authorizations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		definition := parser.Listing.Authorizations[name]
+		if definition.Type == "apiKey" && definition.Keyname == "" {
+			delete(parser.Listing.Authorizations, name)
+			parser.Warnings = append(parser.Warnings, &ParseError{File: mainApiFile, Annotation: annotations[definition], Err: fmt.Errorf("@Name of API key %s is missing, skipped.", name)})
+		}
+	}
+	if len(parser.Listing.Authorizations) == 0 {
+		parser.Listing.Authorizations = nil
+	}
+}
+
+// setGrantTypes describes the OAuth2 flow the way Swagger 1.2 does, it only knows implicit and authorization code grants
+func (a *SecurityDefinition) setGrantTypes() {
+	switch a.Flow {
+	case "implicit":
+		a.GrantTypes = map[string]SecurityGrantType{
+			"implicit": {LoginEndpoint: &SecurityEndpoint{Url: a.AuthorizationUrl}},
+		}
+	case "accessCode":
+		a.GrantTypes = map[string]SecurityGrantType{
+			"authorization_code": {
+				TokenRequestEndpoint: &SecurityEndpoint{Url: a.AuthorizationUrl},
+				TokenEndpoint:        &SecurityEndpoint{Url: a.TokenUrl},
+			},
+		}
+	}
+}
+
+// @Security OAuth2 read,write
+func (operation *Operation) ParseSecurityComment(commentLine string) error {
+	fields := strings.Fields(commentLine)
+	if len(fields) == 0 || len(fields) > 2 {
+		return fmt.Errorf("Can not parse security comment \"%s\", skipped.", commentLine)
+	}
+
+	scopes := make([]Scope, 0)
+	if len(fields) == 2 {
+		for _, scope := range strings.Split(fields[1], ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, Scope{Scope: scope})
+			}
+		}
+	}
+	if operation.Security == nil {
+		operation.Security = make(map[string][]Scope)
+	}
+	operation.Security[fields[0]] = scopes
+	return nil
+}
@@ -21,6 +21,12 @@ type ResourceListing struct {
 	BasePath       string     `json:"basePath,omitempty"`
 	Apis           []*ApiRef  `json:"apis"`
 	Infos          Infomation `json:"info"`
+	// Security definitions, referenced by the @Security annotation of operations
+	Authorizations map[string]*SecurityDefinition `json:"authorizations,omitempty"`
+	// Descriptions of the groups of operations declared by @Tag, by tag name
+	TagDescriptions map[string]string `json:"-"`
+	// Base paths of the named environments declared by @Environment, by name
+	Environments map[string]string `json:"environments,omitempty"`
 }
 
 type ApiRef struct {
@@ -53,23 +59,36 @@ type Protocol struct {
 }
 
 type ResponseMessage struct {
-	Code          int    `json:"code"`
-	Message       string `json:"message"`
-	ResponseType  string `json:"responseType"`
-	ResponseModel string `json:"responseModel"`
+	Code          int              `json:"code"`
+	Message       string           `json:"message"`
+	ResponseType  string           `json:"responseType"`
+	ResponseModel string           `json:"responseModel"`
+	Headers       []ResponseHeader `json:"headers,omitempty"`
+	// Example bodies of the response by content type, e.g. application/json
+	Examples map[string]string `json:"examples,omitempty"`
+}
+
+type ResponseHeader struct {
+	Name        string `json:"name"` // e.g. X-Total-Count
+	Type        string `json:"type"`
+	Format      string `json:"format,omitempty"`
+	Description string `json:"description,omitempty"`
 }
 
 type Parameter struct {
-	ParamType     string `json:"paramType"` // path,query,body,header,form
-	Name          string `json:"name"`
-	Description   string `json:"description"`
-	DataType      string `json:"dataType"` // 1.2 needed?
-	Type          string `json:"type"`     // integer
-	Format        string `json:"format"`   // int64
-	AllowMultiple bool   `json:"allowMultiple"`
-	Required      bool   `json:"required"`
-	Minimum       int    `json:"minimum"`
-	Maximum       int    `json:"maximum"`
+	ParamType     string   `json:"paramType"` // path,query,body,header,form
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	DataType      string   `json:"dataType"` // 1.2 needed?
+	Type          string   `json:"type"`     // integer
+	Format        string   `json:"format"`   // int64
+	AllowMultiple bool     `json:"allowMultiple"`
+	Required      bool     `json:"required"`
+	Minimum       int      `json:"minimum"`
+	Maximum       int      `json:"maximum"`
+	Enum          []string `json:"enum,omitempty"`
+	DefaultValue  string   `json:"defaultValue,omitempty"`
+	Example       string   `json:"example,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -78,12 +97,16 @@ type ErrorResponse struct {
 }
 
 // https://github.com/wordnik/swagger-core/wiki/authorizations
+//
+// Deprecated: use SecurityDefinition, the parser does not fill it
 type Authorization struct {
 	LocalOAuth OAuth  `json:"local-oauth"`
 	ApiKey     ApiKey `json:"apiKey"`
 }
 
 // https://github.com/wordnik/swagger-core/wiki/authorizations
+//
+// Deprecated: use SecurityDefinition, the parser does not fill it
 type OAuth struct {
 	Type       string               `json:"type"`   // e.g. oauth2
 	Scopes     []string             `json:"scopes"` // e.g. PUBLIC
@@ -91,6 +114,8 @@ type OAuth struct {
 }
 
 // https://github.com/wordnik/swagger-core/wiki/authorizations
+//
+// Deprecated: use SecurityGrantType, the parser does not fill it
 type GrantType struct {
 	LoginEndpoint        Endpoint `json:"loginEndpoint"`
 	TokenName            string   `json:"tokenName"` // e.g. access_code
@@ -99,6 +124,8 @@ type GrantType struct {
 }
 
 // https://github.com/wordnik/swagger-core/wiki/authorizations
+//
+// Deprecated: use SecurityEndpoint, the parser does not fill it
 type Endpoint struct {
 	Url              string `json:"url"`
 	ClientIdName     string `json:"clientIdName"`
@@ -107,7 +134,46 @@ type Endpoint struct {
 }
 
 // https://github.com/wordnik/swagger-core/wiki/authorizations
+//
+// Deprecated: use SecurityDefinition, the parser does not fill it
 type ApiKey struct {
 	Type   string `json:"type"`   // e.g. apiKey
 	PassAs string `json:"passAs"` // e.g. header
 }
+
+// Security definition declared by @SecurityDefinitions
+// https://github.com/swagger-api/swagger-spec/blob/master/versions/1.2.md#514-authorization-object
+type SecurityDefinition struct {
+	Type       string                       `json:"type"`              // basicAuth, apiKey or oauth2
+	PassAs     string                       `json:"passAs,omitempty"`  // header or query
+	Keyname    string                       `json:"keyname,omitempty"` // e.g. Authorization
+	Scopes     []Scope                      `json:"scopes,omitempty"`
+	GrantTypes map[string]SecurityGrantType `json:"grantTypes,omitempty"`
+
+	// OAuth2 flow (implicit, password, application or accessCode) and its endpoints, as Swagger 2.0 describes them
+	Flow             string `json:"-"`
+	AuthorizationUrl string `json:"-"`
+	TokenUrl         string `json:"-"`
+}
+
+// https://github.com/swagger-api/swagger-spec/blob/master/versions/1.2.md#516-scope-object
+type Scope struct {
+	Scope       string `json:"scope"` // e.g. write:pets
+	Description string `json:"description,omitempty"`
+}
+
+// https://github.com/swagger-api/swagger-spec/blob/master/versions/1.2.md#517-grant-types-object
+type SecurityGrantType struct {
+	LoginEndpoint        *SecurityEndpoint `json:"loginEndpoint,omitempty"`
+	TokenName            string            `json:"tokenName,omitempty"` // e.g. access_code
+	TokenRequestEndpoint *SecurityEndpoint `json:"tokenRequestEndpoint,omitempty"`
+	TokenEndpoint        *SecurityEndpoint `json:"tokenEndpoint,omitempty"`
+}
+
+// https://github.com/swagger-api/swagger-spec/blob/master/versions/1.2.md#5110-token-request-endpoint-object
+type SecurityEndpoint struct {
+	Url              string `json:"url"`
+	ClientIdName     string `json:"clientIdName,omitempty"`
+	ClientSecretName string `json:"clientSecretName,omitempty"`
+	TokenName        string `json:"tokenName,omitempty"`
+}
@@ -0,0 +1,203 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Import implements types.Importer, so imported packages are type checked from the same sources
+// (GOPATH, vendor dirs, modules) the parser reads annotations from
+func (parser *Parser) Import(packagePath string) (*types.Package, error) {
+	return parser.ImportFrom(packagePath, "", 0)
+}
+
+// ImportFrom implements types.ImporterFrom
+func (parser *Parser) ImportFrom(packagePath, dir string, mode types.ImportMode) (*types.Package, error) {
+	if packagePath == "unsafe" {
+		return types.Unsafe, nil
+	}
+
+	pkgRealPath := parser.CheckRealPackagePath(packagePath)
+	if pkgRealPath == "" {
+		return nil, fmt.Errorf("Can not find package %s", packagePath)
+	}
+
+	if pkg, ok := parser.TypesPackages[pkgRealPath]; ok {
+		if pkg == nil {
+			return nil, fmt.Errorf("Import cycle while type checking package %s", packagePath)
+		}
+		return pkg, nil
+	}
+	return parser.typeCheckPackage(packagePath, pkgRealPath)
+}
+
+// TypesInfo returns the type information of the package, type checking it if required.
+// It returns nil if the package can not be type checked
+func (parser *Parser) TypesInfo(packageName string) *types.Info {
+	pkgRealPath := parser.CheckRealPackagePath(packageName)
+	if pkgRealPath == "" {
+		return nil
+	}
+	if _, ok := parser.TypesPackages[pkgRealPath]; !ok {
+		if _, err := parser.typeCheckPackage(packageName, pkgRealPath); err != nil {
+			log.Debugf("Can not type check package %s: %v", packageName, err)
+		}
+	}
+	return parser.TypesInfos[pkgRealPath]
+}
+
+func (parser *Parser) typeCheckPackage(packagePath, pkgRealPath string) (*types.Package, error) {
+	files, err := parser.packageFiles(pkgRealPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("No buildable Go source files in %s", pkgRealPath)
+	}
+
+	// Mark the package as in progress, to detect import cycles
+	parser.TypesPackages[pkgRealPath] = nil
+
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	typeErrors := 0
+	config := &types.Config{
+		Importer:    parser,
+		FakeImportC: true,
+		// Keep going on errors: missing dependencies or unsupported build configurations
+		// should only affect the types which really use them
+		Error: func(err error) {
+			if typeErrors == 0 {
+				log.Debugf("Type check of %s: %v", packagePath, err)
+			}
+			typeErrors++
+		},
+	}
+	pkg, _ := config.Check(packagePath, parser.FileSet, files, info)
+
+	parser.TypesPackages[pkgRealPath] = pkg
+	parser.TypesInfos[pkgRealPath] = info
+	return pkg, nil
+}
+
+// packageFiles returns the files of the package which match the build constraints of the current platform
+func (parser *Parser) packageFiles(pkgRealPath string) ([]*ast.File, error) {
+	astPackages, err := parser.GetPackageAst(pkgRealPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var packageName string
+	filesByPackage := make(map[string][]string)
+	for name, astPackage := range astPackages {
+		for fileName := range astPackage.Files {
+			if match, err := build.Default.MatchFile(filepath.Dir(fileName), filepath.Base(fileName)); err == nil && match {
+				filesByPackage[name] = append(filesByPackage[name], fileName)
+			}
+		}
+		// Directories may contain files of other packages, excluded by build tags (e.g. "package main" generators)
+		if len(filesByPackage[name]) > len(filesByPackage[packageName]) {
+			packageName = name
+		}
+	}
+
+	fileNames := filesByPackage[packageName]
+	sort.Strings(fileNames)
+
+	files := make([]*ast.File, 0, len(fileNames))
+	for _, fileName := range fileNames {
+		files = append(files, astPackages[packageName].Files[fileName])
+	}
+	return files, nil
+}
+
+// TypeOfExpr returns the type of the expression found in the sources of the package,
+// or nil if the package has no type information
+func (parser *Parser) TypeOfExpr(expr ast.Expr, packageName string) types.Type {
+	info := parser.TypesInfo(packageName)
+	if info == nil {
+		return nil
+	}
+	return info.TypeOf(expr)
+}
+
+// TypeString renders a type the same way annotations reference types: basic types by their name,
+// slices as []Type, maps as map[Key]Type, named types qualified with the import path of their package (github.com/foo/bar.Type)
+func (parser *Parser) TypeString(t types.Type) string {
+	switch t := t.(type) {
+	case *types.Basic:
+		if t.Kind() == types.Invalid {
+			return ""
+		}
+		if t.Kind() == types.UnsafePointer {
+			return "uintptr"
+		}
+		return t.Name()
+	case *types.Pointer:
+		return parser.TypeString(t.Elem())
+	case *types.Slice:
+		return "[]" + parser.TypeString(t.Elem())
+	case *types.Array:
+		return "[]" + parser.TypeString(t.Elem())
+	case *types.Map:
+		return "map[" + parser.TypeString(t.Key()) + "]" + parser.TypeString(t.Elem())
+	case *types.Named:
+		obj := t.Obj()
+		if obj.Pkg() == nil {
+			// predeclared error
+			return obj.Name()
+		}
+		if obj.Pkg().Path() == "time" && obj.Name() == "Time" {
+			return "Time"
+		}
+		if parser.isPrimitiveType(t) {
+			// Documented by MarshalerType, whatever the underlying type is
+			return obj.Pkg().Path() + "." + obj.Name()
+		}
+		switch underlying := t.Underlying().(type) {
+		case *types.Basic, *types.Slice, *types.Array, *types.Map:
+			// Named basic and collection types (type Status string, type Tags []string) are documented as the underlying type
+			return parser.TypeString(underlying)
+		}
+		if typeArguments := t.TypeArgs(); typeArguments.Len() > 0 {
+			// Instantiated generic type: github.com/foo/bar.Page[github.com/foo/bar.User]
+			arguments := make([]string, 0, typeArguments.Len())
+			for i := 0; i < typeArguments.Len(); i++ {
+				arguments = append(arguments, parser.TypeString(typeArguments.At(i)))
+			}
+			return obj.Pkg().Path() + "." + obj.Name() + "[" + strings.Join(arguments, ",") + "]"
+		}
+		return obj.Pkg().Path() + "." + obj.Name()
+	}
+
+	// Interfaces, anonymous structs, functions and channels can hold anything
+	return "interface"
+}
+
+// Position returns the file:line of the node
+func (parser *Parser) Position(pos token.Pos) string {
+	position := parser.FileSet.Position(pos)
+	return fmt.Sprintf("%s:%d", position.Filename, position.Line)
+}
+
+// splitQualifiedTypeName splits github.com/foo/bar.Type into package path and type name.
+// Names which are not qualified with an import path are returned unchanged as type name
+func splitQualifiedTypeName(typeName string) (string, string) {
+	// Type arguments of generic types are qualified too
+	genericName, _ := splitTypeArguments(typeName)
+	slash := strings.LastIndex(genericName, "/")
+	dot := strings.LastIndex(genericName, ".")
+	if slash == -1 || dot < slash {
+		return "", typeName
+	}
+	return typeName[:dot], typeName[dot+1:]
+}
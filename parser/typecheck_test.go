@@ -0,0 +1,284 @@
+package parser_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/yvasiyarov/swagger/parser"
+)
+
+type TypeCheckSuite struct {
+	suite.Suite
+	root   string
+	parser *parser.Parser
+}
+
+var typeCheckSources = map[string]string{
+	"go.mod": "module example.com/typed\n",
+	"models/user.go": `package models
+
+type User struct {
+	Id      int64
+	Profile *Profile
+	Login   string // Deprecated: use Profile.Nick
+}
+`,
+	"models/profile.go": `package models
+
+type Status string
+
+const (
+	StatusActive  Status = "active"
+	StatusBlocked Status = "blocked"
+	statusCount          = 2
+)
+
+type Level int
+
+const (
+	LevelLow Level = iota + 1
+	_
+	LevelHigh
+)
+
+type Profile struct {
+	Nick   string
+	Status Status
+	Levels []Level
+}
+`,
+	"accounts/user.go": `package accounts
+
+type User struct {
+	Email string
+}
+`,
+	"common/meta.go": `package common
+
+type Meta struct {
+	Total int
+}
+`,
+	"common/page.go": `package common
+
+type Page[T any] struct {
+	Items []T
+	Next  string
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value *V
+}
+
+type Reply struct {
+	Data interface{} ` + "`json:\"data\"`" + `
+	Meta interface{} ` + "`json:\"meta\"`" + `
+	Code int         ` + "`json:\"code\"`" + `
+}
+`,
+	"api/api.go": `package api
+
+import (
+	"example.com/typed/accounts"
+	. "example.com/typed/common"
+	m "example.com/typed/models"
+)
+
+type Response struct {
+	Owner   *m.User
+	Users   []m.User
+	Meta    Meta
+	Missing UndefinedType
+	Index   map[string]*m.User
+	Groups  map[string][]m.User
+	Counts  map[int]map[string]int
+}
+
+type Envelope struct {
+	Users Page[m.User]
+	Pairs []Pair[string, m.Profile]
+}
+
+type Directory struct {
+	Members  Page[m.User]
+	Accounts Page[accounts.User]
+}
+`,
+}
+
+func (suite *TypeCheckSuite) SetupSuite() {
+	var err error
+	suite.root, err = ioutil.TempDir("", "swagger-typecheck")
+	assert.NoError(suite.T(), err, "Can not create temp dir")
+
+	for name, content := range typeCheckSources {
+		name = filepath.Join(suite.root, filepath.FromSlash(name))
+		assert.NoError(suite.T(), os.MkdirAll(filepath.Dir(name), 0777), "Can not create dir")
+		assert.NoError(suite.T(), ioutil.WriteFile(name, []byte(content), 0666), "Can not write file")
+	}
+
+	suite.parser, err = parser.NewParser(filepath.Join(suite.root, "api"), "", "^$", "", false)
+	assert.NoError(suite.T(), err, "Unable to complete suite initialization")
+	assert.Equal(suite.T(), []string{"example.com/typed/api"}, suite.parser.APIPackages, "API package dir not translated to import path")
+
+	err = suite.parser.ParseTypeDefinitions("example.com/typed/api")
+	assert.NoError(suite.T(), err, "Can not parse type definitions")
+}
+
+func (suite *TypeCheckSuite) TearDownSuite() {
+	os.RemoveAll(suite.root)
+}
+
+func (suite *TypeCheckSuite) TestResolveFieldTypes() {
+	m := parser.NewModel(suite.parser)
+	err, innerModels := m.ParseModel("Response", "example.com/typed/api", map[string]bool{})
+	assert.Nil(suite.T(), err, "Can not parse Response definition")
+	assert.Equal(suite.T(), "example.com.typed.api.Response", m.Id, "Model id not built from import path")
+
+	assert.Equal(suite.T(), "example.com.typed.models.User", m.Properties["Owner"].Type, "Pointer to aliased import not resolved")
+	assert.Equal(suite.T(), "array", m.Properties["Users"].Type, "Slice of aliased import not resolved")
+	assert.Equal(suite.T(), "example.com.typed.models.User", m.Properties["Users"].Items.Ref, "Slice of aliased import not resolved")
+	assert.Equal(suite.T(), "example.com.typed.common.Meta", m.Properties["Meta"].Type, "Dot import not resolved")
+	assert.Equal(suite.T(), "interface", m.Properties["Missing"].Type, "Unresolved type must not stop parsing")
+	assert.False(suite.T(), m.Properties["Owner"].Deprecated, "Fields are not deprecated by default")
+
+	assert.Equal(suite.T(), "object", m.Properties["Index"].Type, "Map not resolved as object")
+	assert.Equal(suite.T(), "example.com.typed.models.User", m.Properties["Index"].AdditionalProperties.Type, "Map of models not resolved")
+	assert.Equal(suite.T(), "array", m.Properties["Groups"].AdditionalProperties.Type, "Map of slices not resolved")
+	assert.Equal(suite.T(), "example.com.typed.models.User", m.Properties["Groups"].AdditionalProperties.Items.Ref, "Map of slices not resolved")
+	assert.Equal(suite.T(), "object", m.Properties["Counts"].AdditionalProperties.Type, "Nested map not resolved")
+	assert.Equal(suite.T(), "integer", m.Properties["Counts"].AdditionalProperties.AdditionalProperties.Type, "Nested map not resolved")
+
+	modelIds := make([]string, 0, len(innerModels))
+	for _, innerModel := range innerModels {
+		modelIds = append(modelIds, innerModel.Id)
+		if innerModel.Id == "example.com.typed.models.User" {
+			assert.True(suite.T(), innerModel.Properties["Login"].Deprecated, "Deprecated: comment of field not recognized")
+		}
+		if innerModel.Id == "example.com.typed.models.Profile" {
+			assert.Equal(suite.T(), "string", innerModel.Properties["Status"].Type, "Named basic type not resolved to its underlying type")
+			assert.Equal(suite.T(), []string{"active", "blocked"}, innerModel.Properties["Status"].Enum, "Constants of named type not used as enum")
+			assert.Equal(suite.T(), []string{"1", "3"}, innerModel.Properties["Levels"].Items.Enum, "Constants of slice item type not used as enum")
+		}
+	}
+	assert.ElementsMatch(suite.T(), []string{
+		"example.com.typed.models.User",
+		"example.com.typed.models.Profile",
+		"example.com.typed.common.Meta",
+	}, modelIds, "Models declared in other files of imported packages not found")
+}
+
+func (suite *TypeCheckSuite) TestGenericTypes() {
+	m := parser.NewModel(suite.parser)
+	err, innerModels := m.ParseModel("Envelope", "example.com/typed/api", map[string]bool{})
+	assert.Nil(suite.T(), err, "Can not parse Envelope definition")
+
+	assert.Equal(suite.T(), "example.com.typed.common.Page_User", m.Properties["Users"].Type, "Instantiated generic field not resolved")
+	assert.Equal(suite.T(), "example.com.typed.common.Pair_string_Profile", m.Properties["Pairs"].Items.Ref, "Slice of instantiated generic type not resolved")
+
+	models := make(map[string]*parser.Model)
+	for _, innerModel := range innerModels {
+		models[innerModel.Id] = innerModel
+	}
+	if page, ok := models["example.com.typed.common.Page_User"]; assert.True(suite.T(), ok, "Model of instantiation not parsed") {
+		assert.Equal(suite.T(), "example.com.typed.models.User", page.Properties["Items"].Items.Ref, "Type parameter not substituted")
+		assert.Equal(suite.T(), "string", page.Properties["Next"].Type, "Can not parse Page definition")
+	}
+	if pair, ok := models["example.com.typed.common.Pair_string_Profile"]; assert.True(suite.T(), ok, "Model of instantiation not parsed") {
+		assert.Equal(suite.T(), "string", pair.Properties["Key"].Type, "Type parameter not substituted")
+		assert.Equal(suite.T(), "example.com.typed.models.Profile", pair.Properties["Value"].Type, "Type parameter not substituted")
+	}
+
+	suite.parser.CurrentPackage = "example.com/typed/api"
+	op := parser.NewOperation(suite.parser, "example.com/typed/api")
+	assert.NoError(suite.T(), op.ParseResponseComment(`200 {object} Page[m.User] "Users"`), "Can not parse generic response type")
+	assert.Equal(suite.T(), "example.com.typed.common.Page_User", op.Type, "Generic response type not instantiated")
+	assert.NoError(suite.T(), op.ParseResponseComment(`201 {array} Pair[string,int] "Pairs"`), "Can not parse generic response type")
+	assert.Equal(suite.T(), "example.com.typed.common.Pair_string_int", op.ResponseMessages[1].ResponseModel, "Generic response type not instantiated")
+}
+
+func (suite *TypeCheckSuite) TestGenericNameClash() {
+	// A parser of its own, the models named by the other tests must not change
+	p, err := parser.NewParser(filepath.Join(suite.root, "api"), "", "^$", "", false)
+	assert.NoError(suite.T(), err, "Can not create parser")
+	assert.NoError(suite.T(), p.ParseTypeDefinitions("example.com/typed/api"), "Can not parse type definitions")
+
+	m := parser.NewModel(p)
+	err, innerModels := m.ParseModel("Directory", "example.com/typed/api", map[string]bool{})
+	assert.Nil(suite.T(), err, "Can not parse Directory definition")
+
+	// The instance parsed first keeps the short name
+	members, accounts := m.Properties["Members"].Type, m.Properties["Accounts"].Type
+	assert.NotEqual(suite.T(), members, accounts, "Instances with the same name must get distinct models")
+	assert.Subset(suite.T(), []string{"example.com.typed.common.Page_User", "example.com.typed.common.Page_example_com_typed_accounts_User"}, []string{accounts}, "Clashing instance not named after the package of its type argument")
+
+	models := make(map[string]*parser.Model)
+	for _, innerModel := range innerModels {
+		models[innerModel.Id] = innerModel
+	}
+	if page, ok := models[members]; assert.True(suite.T(), ok, "Model of instantiation not parsed") {
+		assert.Equal(suite.T(), "example.com.typed.models.User", page.Properties["Items"].Items.Ref, "Model of instantiation overwritten")
+	}
+	if page, ok := models[accounts]; assert.True(suite.T(), ok, "Model of instantiation not parsed") {
+		assert.Equal(suite.T(), "example.com.typed.accounts.User", page.Properties["Items"].Items.Ref, "Model of instantiation overwritten")
+	}
+
+	warnings := 0
+	for _, warning := range p.Warnings {
+		if strings.Contains(warning.Error(), "Model name Page_User") {
+			warnings++
+		}
+	}
+	assert.Equal(suite.T(), 1, warnings, "Name clash must be reported once")
+}
+
+func (suite *TypeCheckSuite) TestCompositeResponse() {
+	suite.parser.CurrentPackage = "example.com/typed/api"
+	op := parser.NewOperation(suite.parser, "example.com/typed/api")
+	assert.NoError(suite.T(), op.ParseResponseComment(`200 {object} Reply{data=[]m.User,meta=Page[m.Profile]} "ok"`), "Can not parse composite response type")
+	assert.NoError(suite.T(), op.ParseResponseComment(`201 {object} Reply{data=Reply{code=string}} "Nested"`), "Can not parse nested composite response type")
+	assert.Error(suite.T(), op.ParseResponseComment(`400 {object} Reply{missing=string} "Error"`), "Unknown fields must not be overridden")
+
+	assert.Equal(suite.T(), "example.com.typed.common.Reply_data_array_User_meta_Page_Profile", op.Type, "Composite response type not registered")
+	models := make(map[string]*parser.Model)
+	for _, model := range op.Models {
+		models[model.Id] = model
+	}
+	if reply, ok := models[op.Type]; assert.True(suite.T(), ok, "Model of composite type not registered") {
+		assert.Equal(suite.T(), "array", reply.Properties["data"].Type, "Field type not overridden")
+		assert.Equal(suite.T(), "example.com.typed.models.User", reply.Properties["data"].Items.Ref, "Field type not overridden")
+		assert.Equal(suite.T(), "example.com.typed.common.Page_Profile", reply.Properties["meta"].Type, "Field type not overridden by generic type")
+		assert.Equal(suite.T(), "integer", reply.Properties["code"].Type, "Other fields must be kept")
+	}
+	assert.Contains(suite.T(), models, "example.com.typed.common.Page_Profile", "Models of overridden fields not registered")
+	if nested, ok := models["example.com.typed.common.Reply_data_Reply_code_string"]; assert.True(suite.T(), ok, "Model of nested composite type not registered") {
+		assert.Equal(suite.T(), "example.com.typed.common.Reply_code_string", nested.Properties["data"].Type, "Nested composite type not registered")
+	}
+	assert.Equal(suite.T(), "string", models["example.com.typed.common.Reply_code_string"].Properties["code"].Type, "Field type not overridden")
+}
+
+func (suite *TypeCheckSuite) TestParamEnums() {
+	suite.parser.CurrentPackage = "example.com/typed/models"
+	op := parser.NewOperation(suite.parser, "example.com/typed/models")
+
+	assert.NoError(suite.T(), op.ParseParamComment(`status query Status true "User status"`), "Can not parse param comment")
+	assert.NoError(suite.T(), op.ParseParamComment(`level query Level false "Level" Enums(1, 2)`), "Can not parse param comment")
+	assert.NoError(suite.T(), op.ParseParamComment(`nick query string false "Nick"`), "Can not parse param comment")
+
+	if assert.Len(suite.T(), op.Parameters, 3, "Params not parsed") {
+		assert.Equal(suite.T(), "string", op.Parameters[0].DataType, "Named basic type not resolved to its underlying type")
+		assert.Equal(suite.T(), []string{"active", "blocked"}, op.Parameters[0].Enum, "Constants of named type not used as enum")
+		assert.Equal(suite.T(), []string{"1", "2"}, op.Parameters[1].Enum, "Enums() must override the constants")
+		assert.Nil(suite.T(), op.Parameters[2].Enum, "Basic types have no enum")
+	}
+}
+
+func TestTypeCheckSuite(t *testing.T) {
+	suite.Run(t, &TypeCheckSuite{})
+}
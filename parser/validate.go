@@ -0,0 +1,145 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Formats of go-playground/validator rules
+var validateFormats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"uuid":     "uuid",
+	"uuid4":    "uuid",
+	"hostname": "hostname",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+}
+
+// Patterns of go-playground/validator rules
+var validatePatterns = map[string]string{
+	"alpha":       "^[a-zA-Z]+$",
+	"alphanum":    "^[a-zA-Z0-9]+$",
+	"numeric":     "^[-+]?[0-9]+(?:\\.[0-9]+)?$",
+	"number":      "^[0-9]+$",
+	"hexadecimal": "^(0[xX])?[0-9a-fA-F]+$",
+	"lowercase":   "^[^A-Z]*$",
+	"uppercase":   "^[^a-z]*$",
+}
+
+var oneOfValue = regexp.MustCompile(`'([^']*)'|(\S+)`)
+
+// ParseValidateTag translates go-playground/validator rules (validate:"min=1,max=64,email")
+// into constraints of the property. It returns true if the rules make the field required
+func (p *ModelProperty) ParseValidateTag(tag string) bool {
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		if strings.Contains(rule, "|") {
+			// Alternatives can not be expressed as constraints
+			continue
+		}
+
+		name, param := rule, ""
+		if i := strings.Index(rule, "="); i != -1 {
+			name, param = rule[:i], rule[i+1:]
+		}
+
+		switch name {
+		case "dive":
+			// Following rules apply to the items
+			return required
+		case "required":
+			required = true
+		case "min", "gte":
+			p.setLowerBound(param, false)
+		case "max", "lte":
+			p.setUpperBound(param, false)
+		case "gt":
+			p.setLowerBound(param, true)
+		case "lt":
+			p.setUpperBound(param, true)
+		case "len":
+			p.setLowerBound(param, false)
+			p.setUpperBound(param, false)
+		case "oneof":
+			p.Enum = nil
+			for _, match := range oneOfValue.FindAllStringSubmatch(param, -1) {
+				if match[2] != "" {
+					p.Enum = append(p.Enum, match[2])
+				} else {
+					p.Enum = append(p.Enum, match[1])
+				}
+			}
+		default:
+			if format, ok := validateFormats[name]; ok {
+				p.Format = format
+			} else if pattern, ok := validatePatterns[name]; ok {
+				p.Pattern = pattern
+			}
+		}
+	}
+	return required
+}
+
+// setLowerBound sets the minimum of numbers, the minimum length of strings or the minimum number of items
+func (p *ModelProperty) setLowerBound(param string, exclusive bool) {
+	if isNumericType(p.Type) {
+		if value, err := strconv.ParseFloat(param, 64); err == nil {
+			p.Minimum = &value
+			p.ExclusiveMinimum = exclusive
+		}
+		return
+	}
+
+	value, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		return
+	}
+	if exclusive {
+		value++
+	}
+	switch p.Type {
+	case "string":
+		p.MinLength = &value
+	case "array":
+		p.MinItems = &value
+	}
+}
+
+// setUpperBound sets the maximum of numbers, the maximum length of strings or the maximum number of items
+func (p *ModelProperty) setUpperBound(param string, exclusive bool) {
+	if isNumericType(p.Type) {
+		if value, err := strconv.ParseFloat(param, 64); err == nil {
+			p.Maximum = &value
+			p.ExclusiveMaximum = exclusive
+		}
+		return
+	}
+
+	value, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		return
+	}
+	if exclusive {
+		value--
+	}
+	switch p.Type {
+	case "string":
+		p.MaxLength = &value
+	case "array":
+		p.MaxItems = &value
+	}
+}
+
+func isNumericType(typeName string) bool {
+	switch typeName {
+	case "integer", "number",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64", "byte", "rune", "uintptr":
+		return true
+	}
+	return false
+}
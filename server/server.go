@@ -0,0 +1,275 @@
+// Package server serves a generated Swagger 1.2 spec together with the bundled Swagger UI
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	swaggerui "github.com/yvasiyarov/swagger/swagger-ui"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	uiPath          = "/swagger-ui/"
+	environmentPath = "/env/"
+)
+
+// Handler serves the resource listing at Prefix + "/", the API declarations at Prefix + "/<resource>"
+// and Swagger UI at Prefix + "/swagger-ui/".
+// The spec of a named environment is served at Prefix + "/env/<name>/"
+type Handler struct {
+	// Path the handler is mounted at, e.g. "/docs". Empty for the root
+	Prefix string
+	// Base URL of the API, replaces the {{.}} base path of the API declarations.
+	// The URL the request was sent to, behind any proxies, is used if it is empty
+	ApiURL string
+	// Environment declared by @Environment whose base path is used by default
+	Environment string
+	// Hosts the base path may point to. If the request was sent to another host, according to
+	// the Host and X-Forwarded-Host headers, the first trusted host is used. Any host is trusted if it is empty
+	TrustedHosts []string
+	// Files of Swagger UI, swaggerui.Files by default
+	UI fs.FS
+
+	resourceListing map[string]interface{}
+	apiDescriptions map[string]map[string]interface{}
+	environments    map[string]string
+}
+
+// New returns a handler for the ResourceListingJson and ApiDescriptionsJson of a file generated by -format gopkg.
+// The spec may also be encoded as YAML, like the files read by NewFromDir
+func New(prefix, resourceListing string, apiDescriptions map[string]string) (*Handler, error) {
+	handler := &Handler{
+		Prefix:          strings.TrimSuffix(prefix, "/"),
+		UI:              swaggerui.Files,
+		apiDescriptions: make(map[string]map[string]interface{}, len(apiDescriptions)),
+		environments:    make(map[string]string),
+	}
+	if err := unmarshalSpec(resourceListing, &handler.resourceListing); err != nil {
+		return nil, fmt.Errorf("Can not parse resource listing: %v", err)
+	}
+	if environments, ok := handler.resourceListing["environments"].(map[string]interface{}); ok {
+		for name, basePath := range environments {
+			if basePath, ok := basePath.(string); ok {
+				handler.environments[name] = basePath
+			}
+		}
+	}
+	for apiKey, apiDescription := range apiDescriptions {
+		var declaration map[string]interface{}
+		if err := unmarshalSpec(apiDescription, &declaration); err != nil {
+			return nil, fmt.Errorf("Can not parse API declaration %s: %v", apiKey, err)
+		}
+		handler.apiDescriptions[strings.Trim(apiKey, "/")] = declaration
+	}
+	return handler, nil
+}
+
+// NewFromDir returns a handler for the index.json or index.yaml files written by -format swagger into dir
+func NewFromDir(prefix, dir string) (*Handler, error) {
+	indexFile := "index.json"
+	if _, err := os.Stat(filepath.Join(dir, indexFile)); os.IsNotExist(err) {
+		indexFile = "index.yaml"
+	}
+	resourceListing, err := ioutil.ReadFile(filepath.Join(dir, indexFile))
+	if err != nil {
+		return nil, fmt.Errorf("Can not read resource listing: %v", err)
+	}
+
+	apiDescriptions := make(map[string]string)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || info.Name() != indexFile || filepath.Dir(path) == filepath.Clean(dir) {
+			return err
+		}
+		apiDescription, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("Can not read API declaration: %v", err)
+		}
+		apiKey, err := filepath.Rel(dir, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		apiDescriptions[filepath.ToSlash(apiKey)] = string(apiDescription)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return New(prefix, string(resourceListing), apiDescriptions)
+}
+
+func (handler *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	origin, forwardedPrefix := handler.origin(r)
+	prefix := forwardedPrefix + handler.Prefix
+	if !strings.HasPrefix(r.URL.Path, handler.Prefix+"/") {
+		if r.URL.Path == handler.Prefix {
+			http.Redirect(w, r, prefix+"/", http.StatusMovedPermanently)
+		} else {
+			http.NotFound(w, r)
+		}
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, handler.Prefix)
+	if strings.HasPrefix(path, uiPath) {
+		ui := http.FileServer(http.FS(handler.UI))
+		http.StripPrefix(handler.Prefix+uiPath[:len(uiPath)-1], ui).ServeHTTP(w, r)
+		return
+	}
+
+	apiURL := handler.ApiURL
+	if apiURL == "" {
+		apiURL = origin + forwardedPrefix
+	}
+	environment := handler.Environment
+	if strings.HasPrefix(path, environmentPath) {
+		path = strings.TrimPrefix(path, environmentPath)
+		if i := strings.Index(path, "/"); i >= 0 {
+			environment, path = path[:i], path[i:]
+		} else {
+			environment, path = path, "/"
+		}
+		prefix += environmentPath + environment
+	}
+	if environment != "" {
+		var ok bool
+		if apiURL, ok = handler.environments[environment]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	if path == "/" {
+		if acceptsJson(r) {
+			handler.serveResourceListing(w, origin+prefix)
+		} else {
+			http.Redirect(w, r, forwardedPrefix+handler.Prefix+uiPath, http.StatusFound)
+		}
+		return
+	}
+	declaration, ok := handler.apiDescriptions[strings.Trim(path, "/")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	handler.serveApiDescription(w, declaration, apiURL, environment != "")
+}
+
+// serveResourceListing points the base path of the listing to the declarations served by the handler
+func (handler *Handler) serveResourceListing(w http.ResponseWriter, basePath string) {
+	resourceListing := make(map[string]interface{}, len(handler.resourceListing))
+	for key, value := range handler.resourceListing {
+		resourceListing[key] = value
+	}
+	resourceListing["basePath"] = basePath
+	writeJson(w, resourceListing)
+}
+
+// serveApiDescription replaces the {{.}} placeholder of the base path with apiURL.
+// Base paths declared by @BasePath are kept, unless an environment is selected
+func (handler *Handler) serveApiDescription(w http.ResponseWriter, declaration map[string]interface{}, apiURL string, environment bool) {
+	apiDescription := make(map[string]interface{}, len(declaration))
+	for key, value := range declaration {
+		apiDescription[key] = value
+	}
+	basePath, _ := declaration["basePath"].(string)
+	if environment {
+		basePath = apiURL
+	}
+	apiDescription["basePath"] = strings.Replace(basePath, "{{.}}", apiURL, -1)
+	writeJson(w, apiDescription)
+}
+
+// origin returns the scheme and host the request was sent to, and the path prefix stripped by proxies,
+// according to the X-Forwarded-Proto, X-Forwarded-Host and X-Forwarded-Prefix headers.
+// Prefixes which are not plain paths are ignored
+func (handler *Handler) origin(r *http.Request) (string, string) {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := forwardedHeader(r, "X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+
+	host := r.Host
+	if forwardedHost := forwardedHeader(r, "X-Forwarded-Host"); forwardedHost != "" {
+		host = forwardedHost
+	}
+	if !handler.trusted(host) {
+		host = handler.TrustedHosts[0]
+	}
+
+	prefix := strings.TrimSuffix(forwardedHeader(r, "X-Forwarded-Prefix"), "/")
+	if prefix != "" && !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	if !validPrefix(prefix) {
+		prefix = ""
+	}
+	return scheme + "://" + host, prefix
+}
+
+// validPrefix rejects forwarded prefixes which would make redirects and base paths leave the host,
+// like "//evil.com", "/x/http://evil.com" or "/../evil"
+func validPrefix(prefix string) bool {
+	return !strings.HasPrefix(prefix, "//") && !strings.Contains(prefix, "://") && !strings.Contains(prefix, "..") &&
+		!strings.ContainsAny(prefix, "\\?#@ \t\r\n")
+}
+
+func (handler *Handler) trusted(host string) bool {
+	if len(handler.TrustedHosts) == 0 {
+		return true
+	}
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	for _, trustedHost := range handler.TrustedHosts {
+		if strings.EqualFold(trustedHost, host) || strings.EqualFold(trustedHost, hostname) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedHeader returns the value set by the proxy closest to the client
+func forwardedHeader(r *http.Request, name string) string {
+	return strings.TrimSpace(strings.Split(r.Header.Get(name), ",")[0])
+}
+
+// unmarshalSpec decodes a JSON spec, or a YAML one generated with -encoding yaml
+func unmarshalSpec(data string, spec *map[string]interface{}) error {
+	if err := json.Unmarshal([]byte(data), spec); err != nil {
+		if yamlErr := yaml.Unmarshal([]byte(data), spec); yamlErr != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func acceptsJson(r *http.Request) bool {
+	for _, acceptHeader := range r.Header["Accept"] {
+		if strings.Contains(acceptHeader, "json") {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJson(w http.ResponseWriter, value interface{}) {
+	json, err := json.MarshalIndent(value, "", "    ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(json)
+}
@@ -0,0 +1,165 @@
+package server_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/yvasiyarov/swagger/server"
+)
+
+const resourceListingJson = `{
+    "apiVersion": "1.0.0",
+    "swaggerVersion": "1.2",
+    "basePath": "{{.}}",
+    "apis": [{"path": "/user", "description": "Users"}],
+    "environments": {"prod": "https://api.example.com/v1", "dev": "http://dev.example.com/v1"}
+}`
+
+const userJson = `{"apiVersion": "1.0.0", "basePath": "{{.}}", "resourcePath": "/user", "apis": []}`
+
+type ServerSuite struct {
+	suite.Suite
+	handler *server.Handler
+}
+
+func (suite *ServerSuite) SetupTest() {
+	var err error
+	suite.handler, err = server.New("/docs/", resourceListingJson, map[string]string{"user": userJson})
+	assert.NoError(suite.T(), err, "Can not create handler")
+}
+
+func (suite *ServerSuite) get(path string, accept string, headers ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("GET", "http://example.com"+path, nil)
+	if accept != "" {
+		r.Header.Set("Accept", accept)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		r.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	suite.handler.ServeHTTP(w, r)
+	return w
+}
+
+func (suite *ServerSuite) TestResourceListing() {
+	w := suite.get("/docs/", "application/json, */*")
+	assert.Equal(suite.T(), http.StatusOK, w.Code, "Resource listing not served")
+	assert.Equal(suite.T(), "application/json", w.Header().Get("Content-Type"), "Content type not set")
+
+	var listing map[string]interface{}
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &listing), "Resource listing is not JSON")
+	assert.Equal(suite.T(), "http://example.com/docs", listing["basePath"], "Base path must point to the declarations")
+	assert.Equal(suite.T(), "1.0.0", listing["apiVersion"], "Resource listing not kept")
+
+	w = suite.get("/docs/", "text/html")
+	assert.Equal(suite.T(), http.StatusFound, w.Code, "Browsers must be redirected to Swagger UI")
+	assert.Equal(suite.T(), "/docs/swagger-ui/", w.Header().Get("Location"), "Browsers must be redirected to Swagger UI")
+
+	w = suite.get("/docs", "")
+	assert.Equal(suite.T(), "/docs/", w.Header().Get("Location"), "Prefix without slash not redirected")
+	assert.Equal(suite.T(), http.StatusNotFound, suite.get("/other/", "").Code, "Paths outside of the prefix must not be served")
+}
+
+func (suite *ServerSuite) TestApiDescription() {
+	w := suite.get("/docs/user", "application/json")
+	assert.Equal(suite.T(), http.StatusOK, w.Code, "API declaration not served")
+	assert.JSONEq(suite.T(), `{"apiVersion": "1.0.0", "basePath": "http://example.com", "resourcePath": "/user", "apis": []}`, w.Body.String(), "Base path not set to the request URL")
+
+	suite.handler.ApiURL = "https://api.example.com/v1"
+	w = suite.get("/docs/user/", "application/json")
+	assert.Contains(suite.T(), w.Body.String(), `"basePath": "https://api.example.com/v1"`, "Base path not set to ApiURL")
+
+	assert.Equal(suite.T(), http.StatusNotFound, suite.get("/docs/order", "").Code, "Unknown resources must not be found")
+}
+
+func (suite *ServerSuite) basePath(w *httptest.ResponseRecorder) interface{} {
+	var spec map[string]interface{}
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &spec), "Spec is not JSON")
+	return spec["basePath"]
+}
+
+func (suite *ServerSuite) TestForwardedHeaders() {
+	forwarded := []string{
+		"X-Forwarded-Proto", "https, http",
+		"X-Forwarded-Host", "docs.example.com, proxy.local",
+		"X-Forwarded-Prefix", "/svc/",
+	}
+	assert.Equal(suite.T(), "https://docs.example.com/svc/docs", suite.basePath(suite.get("/docs/", "application/json", forwarded...)), "Forwarded headers not used for the listing")
+	assert.Equal(suite.T(), "https://docs.example.com/svc", suite.basePath(suite.get("/docs/user", "application/json", forwarded...)), "Forwarded headers not used for the declarations")
+	assert.Equal(suite.T(), "/svc/docs/swagger-ui/", suite.get("/docs/", "", forwarded...).Header().Get("Location"), "Forwarded prefix not used for redirects")
+
+	suite.handler.TrustedHosts = []string{"api.example.com", "docs.example.com"}
+	assert.Equal(suite.T(), "https://docs.example.com/svc", suite.basePath(suite.get("/docs/user", "application/json", forwarded...)), "Trusted host not used")
+	assert.Equal(suite.T(), "http://api.example.com", suite.basePath(suite.get("/docs/user", "application/json", "X-Forwarded-Host", "evil.com")), "Untrusted host must be replaced")
+	assert.Equal(suite.T(), "http://api.example.com", suite.basePath(suite.get("/docs/user", "application/json")), "Untrusted host must be replaced")
+}
+
+func (suite *ServerSuite) TestHostilePrefix() {
+	for _, prefix := range []string{"//evil.com", "/x/https://evil.com", "/../../evil", "\\\\evil.com", "/svc?next=//evil.com"} {
+		w := suite.get("/docs/", "", "X-Forwarded-Prefix", prefix)
+		assert.Equal(suite.T(), "/docs/swagger-ui/", w.Header().Get("Location"), "Hostile prefix %q used for redirects", prefix)
+		assert.Equal(suite.T(), "/docs/", suite.get("/docs", "", "X-Forwarded-Prefix", prefix).Header().Get("Location"), "Hostile prefix %q used for redirects", prefix)
+		assert.Equal(suite.T(), "http://example.com/docs", suite.basePath(suite.get("/docs/", "application/json", "X-Forwarded-Prefix", prefix)), "Hostile prefix %q used for the listing", prefix)
+	}
+}
+
+func (suite *ServerSuite) TestEnvironments() {
+	assert.Equal(suite.T(), "http://example.com/docs/env/dev", suite.basePath(suite.get("/docs/env/dev/", "application/json")), "Listing of environment not served")
+	assert.Equal(suite.T(), "http://dev.example.com/v1", suite.basePath(suite.get("/docs/env/dev/user", "application/json")), "Base path of environment not used")
+	assert.Equal(suite.T(), http.StatusNotFound, suite.get("/docs/env/test/user", "application/json").Code, "Unknown environments must not be found")
+
+	suite.handler.Environment = "prod"
+	assert.Equal(suite.T(), "https://api.example.com/v1", suite.basePath(suite.get("/docs/user", "application/json")), "Default environment not used")
+}
+
+func (suite *ServerSuite) TestSwaggerUI() {
+	w := suite.get("/docs/swagger-ui/", "")
+	assert.Equal(suite.T(), http.StatusOK, w.Code, "Swagger UI index not served")
+	assert.Contains(suite.T(), w.Body.String(), "swagger-ui-container", "Swagger UI index not served")
+
+	w = suite.get("/docs/swagger-ui/lib/swagger.js", "")
+	assert.Equal(suite.T(), http.StatusOK, w.Code, "Swagger UI assets not served")
+}
+
+func (suite *ServerSuite) TestNewFromDir() {
+	dir, err := ioutil.TempDir("", "swagger-server")
+	assert.NoError(suite.T(), err, "Can not create temp dir")
+	defer os.RemoveAll(dir)
+
+	assert.NoError(suite.T(), os.MkdirAll(filepath.Join(dir, "user"), 0777), "Can not create dir")
+	assert.NoError(suite.T(), ioutil.WriteFile(filepath.Join(dir, "index.json"), []byte(resourceListingJson), 0666), "Can not write file")
+	assert.NoError(suite.T(), ioutil.WriteFile(filepath.Join(dir, "user", "index.json"), []byte(userJson), 0666), "Can not write file")
+
+	suite.handler, err = server.NewFromDir("", dir)
+	assert.NoError(suite.T(), err, "Can not create handler")
+	assert.Equal(suite.T(), http.StatusOK, suite.get("/", "application/json").Code, "Resource listing not served")
+	assert.Equal(suite.T(), http.StatusOK, suite.get("/user", "application/json").Code, "API declaration not served")
+	assert.Equal(suite.T(), http.StatusOK, suite.get("/swagger-ui/", "").Code, "Swagger UI not served")
+
+	_, err = server.NewFromDir("", filepath.Join(dir, "user", "missing"))
+	assert.Error(suite.T(), err, "Missing resource listing must fail")
+}
+
+func (suite *ServerSuite) TestYaml() {
+	var err error
+	suite.handler, err = server.New("", "apiVersion: 1.0.0\nbasePath: '{{.}}'\napis:\n  - path: /user\n", map[string]string{
+		"user": "apiVersion: 1.0.0\nbasePath: '{{.}}'\nresourcePath: /user\n",
+	})
+	assert.NoError(suite.T(), err, "Can not create handler for YAML spec")
+	assert.Equal(suite.T(), "http://example.com", suite.basePath(suite.get("/", "application/json")), "YAML listing not served as JSON")
+	assert.Equal(suite.T(), "http://example.com", suite.basePath(suite.get("/user", "application/json")), "YAML declaration not served as JSON")
+
+	_, err = server.New("", "apis: [", nil)
+	assert.Error(suite.T(), err, "Invalid spec must fail")
+}
+
+func TestServerSuite(t *testing.T) {
+	suite.Run(t, &ServerSuite{})
+}
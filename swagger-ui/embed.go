@@ -0,0 +1,9 @@
+// Package swaggerui bundles the Swagger UI assets, so they can be served without a copy on disk
+package swaggerui
+
+import "embed"
+
+// Files of Swagger UI, index.html is at the root
+//
+//go:embed index.html o2c.html swagger-ui.js swagger-ui.min.js css images lib
+var Files embed.FS
@@ -0,0 +1,87 @@
+package swagger2
+
+import "github.com/yvasiyarov/swagger/convert"
+
+const SwaggerVersion = "2.0"
+
+// http://swagger.io/specification/#swaggerObject
+type Swagger struct {
+	Swagger     string               `json:"swagger"`
+	Info        Info                 `json:"info"`
+	Host        string               `json:"host,omitempty"`
+	BasePath    string               `json:"basePath,omitempty"`
+	Schemes     []string             `json:"schemes,omitempty"`
+	Consumes    []string             `json:"consumes,omitempty"`
+	Produces    []string             `json:"produces,omitempty"`
+	Paths       map[string]*PathItem `json:"paths"`
+	Definitions map[string]*Schema   `json:"definitions,omitempty"`
+	Tags        []*Tag               `json:"tags,omitempty"`
+
+	SecurityDefinitions map[string]*SecurityScheme `json:"securityDefinitions,omitempty"`
+
+	schemas *convert.Schemas
+}
+
+type (
+	Info    = convert.Info
+	Contact = convert.Contact
+	License = convert.License
+	Tag     = convert.Tag
+)
+
+type PathItem = convert.PathItem[Operation]
+
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	OperationId string                `json:"operationId,omitempty"`
+	Consumes    []string              `json:"consumes,omitempty"`
+	Produces    []string              `json:"produces,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+}
+
+// http://swagger.io/specification/#securitySchemeObject
+type SecurityScheme struct {
+	Type             string            `json:"type"` // basic, apiKey or oauth2
+	Name             string            `json:"name,omitempty"`
+	In               string            `json:"in,omitempty"` // query or header
+	Flow             string            `json:"flow,omitempty"`
+	AuthorizationUrl string            `json:"authorizationUrl,omitempty"`
+	TokenUrl         string            `json:"tokenUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // path,query,header,body,formData
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema,omitempty"` // only for "in": "body"
+	Type        string  `json:"type,omitempty"`   // everything except body
+	Format      string  `json:"format,omitempty"`
+	Items       *Schema `json:"items,omitempty"`
+
+	Enum    []interface{} `json:"enum,omitempty"`
+	Default interface{}   `json:"default,omitempty"`
+	Example interface{}   `json:"x-example,omitempty"` // 2.0 has no examples of parameters
+}
+
+type Response struct {
+	Description string                 `json:"description"`
+	Schema      *Schema                `json:"schema,omitempty"`
+	Headers     map[string]*Header     `json:"headers,omitempty"`
+	Examples    map[string]interface{} `json:"examples,omitempty"` // by content type
+}
+
+// http://swagger.io/specification/#headerObject
+type Header struct {
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type"`
+	Format      string `json:"format,omitempty"`
+}
+
+type Schema = convert.Schema
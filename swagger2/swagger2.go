@@ -0,0 +1,189 @@
+package swagger2
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/yvasiyarov/swagger/convert"
+	"github.com/yvasiyarov/swagger/parser"
+)
+
+// NewSwagger converts the Swagger 1.2 resource listing and API declarations collected by the parser
+// into a single Swagger 2.0 document
+func NewSwagger(p *parser.Parser) *Swagger {
+	spec := &Swagger{
+		Swagger: SwaggerVersion,
+		Info:    convert.NewInfo(p.Listing),
+		Paths:   make(map[string]*PathItem),
+		Tags:    convert.NewTags(p.Listing),
+		schemas: &convert.Schemas{
+			RefPrefix:           "#/definitions/",
+			File:                Schema{Type: "file"},
+			DeprecatedExtension: true,
+		},
+	}
+	spec.setBasePath(p.Listing.BasePath)
+
+	for name, authorization := range p.Listing.Authorizations {
+		if spec.SecurityDefinitions == nil {
+			spec.SecurityDefinitions = make(map[string]*SecurityScheme)
+		}
+		spec.SecurityDefinitions[name] = newSecurityScheme(authorization)
+	}
+
+	spec.schemas.AddModels(p)
+	spec.Definitions = spec.schemas.Models
+
+	convert.Operations(p, func(resource, path string, op *parser.Operation) {
+		pathItem, ok := spec.Paths[path]
+		if !ok {
+			pathItem = &PathItem{}
+			spec.Paths[path] = pathItem
+		}
+		pathItem.SetOperation(op.HttpMethod, spec.newOperation(resource, op))
+	})
+
+	return spec
+}
+
+// setBasePath splits the 1.2 absolute base path into the 2.0 schemes, host and basePath fields.
+// Template placeholders like {{.}} can not be expressed in 2.0 and are skipped
+func (spec *Swagger) setBasePath(basePath string) {
+	if basePath == "" || strings.Contains(basePath, "{{") {
+		return
+	}
+	if strings.HasPrefix(basePath, "/") {
+		spec.BasePath = basePath
+		return
+	}
+
+	u, err := url.Parse(basePath)
+	if err != nil || u.Host == "" {
+		return
+	}
+	spec.Host = u.Host
+	if u.Scheme != "" {
+		spec.Schemes = []string{u.Scheme}
+	}
+	spec.BasePath = u.Path
+	if spec.BasePath == "" {
+		spec.BasePath = "/"
+	}
+}
+
+func (spec *Swagger) newOperation(resource string, op *parser.Operation) *Operation {
+	operation := &Operation{
+		Tags:        op.Tags,
+		Summary:     op.Summary,
+		Description: convert.Description(op),
+		OperationId: op.Nickname,
+		Consumes:    op.Consumes,
+		Produces:    op.Produces,
+		Responses:   make(map[string]*Response),
+	}
+
+	if len(operation.Tags) == 0 {
+		operation.Tags = []string{resource}
+	}
+
+	for _, param := range op.Parameters {
+		operation.Parameters = append(operation.Parameters, spec.newParameter(param))
+	}
+
+	for _, msg := range op.ResponseMessages {
+		response := &Response{
+			Description: convert.ResponseDescription(msg),
+		}
+		if msg.ResponseModel != "" {
+			response.Schema = spec.schemas.SchemaForType(msg.ResponseModel)
+			if msg.ResponseType == "array" {
+				response.Schema = &Schema{Type: "array", Items: response.Schema}
+			}
+		}
+		for _, header := range msg.Headers {
+			if response.Headers == nil {
+				response.Headers = make(map[string]*Header)
+			}
+			response.Headers[header.Name] = &Header{Description: header.Description, Type: header.Type, Format: header.Format}
+		}
+		for contentType, example := range msg.Examples {
+			if response.Examples == nil {
+				response.Examples = make(map[string]interface{})
+			}
+			response.Examples[contentType] = convert.ResponseExample(contentType, example)
+		}
+		operation.Responses[strconv.Itoa(msg.Code)] = response
+	}
+
+	if len(operation.Responses) == 0 {
+		operation.Responses[convert.DefaultResponseCode] = &Response{Description: convert.DefaultResponseDescription}
+	}
+
+	operation.Security = convert.SecurityRequirements(op.Security)
+	operation.Deprecated = op.Deprecated
+
+	return operation
+}
+
+func newSecurityScheme(authorization *parser.SecurityDefinition) *SecurityScheme {
+	scheme := &SecurityScheme{Type: authorization.Type}
+	switch authorization.Type {
+	case "basicAuth":
+		scheme.Type = "basic"
+	case "apiKey":
+		scheme.Name, scheme.In = authorization.Keyname, authorization.PassAs
+	case "oauth2":
+		scheme.Flow = authorization.Flow
+		scheme.AuthorizationUrl = authorization.AuthorizationUrl
+		scheme.TokenUrl = authorization.TokenUrl
+		scheme.Scopes = make(map[string]string)
+		for _, scope := range authorization.Scopes {
+			scheme.Scopes[scope.Scope] = scope.Description
+		}
+	}
+	return scheme
+}
+
+func (spec *Swagger) newParameter(param parser.Parameter) *Parameter {
+	parameter := &Parameter{
+		Name:        param.Name,
+		In:          param.ParamType,
+		Description: param.Description,
+		Required:    param.Required,
+	}
+
+	switch param.ParamType {
+	case "body":
+		parameter.Schema = spec.schemas.SchemaForType(param.DataType)
+		if param.Example != "" {
+			parameter.Example = convert.ExampleValue(parameter.Schema, param.Example)
+		}
+		return parameter
+	case "form":
+		parameter.In = "formData"
+	case "path":
+		// Path parameters are always required in 2.0
+		parameter.Required = true
+	}
+
+	schema := spec.schemas.SchemaForType(param.DataType)
+	if schema.Ref != "" || schema.Type == "" || schema.Type == "object" {
+		// Non body parameters can only be primitives, models are passed as strings
+		schema = &Schema{Type: "string"}
+	}
+	parameter.Type = schema.Type
+	parameter.Format = schema.Format
+	parameter.Items = schema.Items
+	for _, value := range param.Enum {
+		parameter.Enum = append(parameter.Enum, convert.EnumValue(parameter.Type, value))
+	}
+	if param.DefaultValue != "" {
+		parameter.Default = convert.ExampleValue(schema, param.DefaultValue)
+	}
+	if param.Example != "" {
+		parameter.Example = convert.ExampleValue(schema, param.Example)
+	}
+
+	return parameter
+}
@@ -0,0 +1,229 @@
+package swagger2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/yvasiyarov/swagger/parser"
+	"github.com/yvasiyarov/swagger/swagger2"
+)
+
+type Swagger2Suite struct {
+	suite.Suite
+	parser *parser.Parser
+}
+
+func (suite *Swagger2Suite) SetupTest() {
+	suite.parser = &parser.Parser{
+		Listing: &parser.ResourceListing{
+			ApiVersion: "1.0.0",
+			BasePath:   "http://127.0.0.1:3000/v1",
+			Infos: parser.Infomation{
+				Title:   "Test API",
+				Contact: "dev@example.com",
+				License: "BSD",
+			},
+		},
+		TopLevelApis: make(map[string]*parser.ApiDeclaration),
+	}
+}
+
+func (suite *Swagger2Suite) addOperation(comments ...string) *parser.Operation {
+	op := parser.NewOperation(suite.parser, "test")
+	for _, comment := range comments {
+		assert.Nil(suite.T(), op.ParseComment(comment), "Can not parse operation comment")
+	}
+	suite.parser.AddOperation(op)
+	return op
+}
+
+func (suite *Swagger2Suite) TestInfo() {
+	spec := swagger2.NewSwagger(suite.parser)
+
+	assert.Equal(suite.T(), "2.0", spec.Swagger, "Swagger version not set")
+	assert.Equal(suite.T(), "Test API", spec.Info.Title, "Title not converted")
+	assert.Equal(suite.T(), "1.0.0", spec.Info.Version, "Version not converted")
+	assert.Equal(suite.T(), "dev@example.com", spec.Info.Contact.Email, "Contact not converted")
+	assert.Equal(suite.T(), "BSD", spec.Info.License.Name, "License not converted")
+
+	assert.Equal(suite.T(), "127.0.0.1:3000", spec.Host, "Host not extracted from base path")
+	assert.Equal(suite.T(), "/v1", spec.BasePath, "Base path not extracted")
+	assert.Equal(suite.T(), []string{"http"}, spec.Schemes, "Scheme not extracted from base path")
+}
+
+func (suite *Swagger2Suite) TestPaths() {
+	suite.addOperation(
+		"// @Title getOrderByNumber",
+		"// @Description Return order by order number",
+		"// @Accept json",
+		"// @Produce json",
+		"// @Param order_nr path int false \"Order number\"",
+		"// @Param note form string false \"Some note\"",
+		"// @Param state query int false \"Order state\" Enums(1,2)",
+		"// @Success 200 {array} string",
+		"// @Header 200 {int64} X-Total-Count \"Total number of orders\"",
+		"// @Failure 400 {object} string \"Order ID must be specified\"",
+		"// @Router /order/by-number/{order_nr} [post]",
+	)
+
+	spec := swagger2.NewSwagger(suite.parser)
+	assert.Len(suite.T(), spec.Paths, 1, "Paths not converted")
+	assert.Len(suite.T(), spec.Tags, 1, "Tags not converted")
+
+	pathItem, ok := spec.Paths["/order/by-number/{order_nr}"]
+	if !ok {
+		suite.T().Fatalf("Can not find path: %#v", spec.Paths)
+	}
+	op := pathItem.Post
+	assert.NotNil(suite.T(), op, "Operation not bound to its http method")
+	assert.Equal(suite.T(), "getOrderByNumber", op.OperationId, "Operation id not converted")
+	assert.Equal(suite.T(), "Return order by order number", op.Summary, "Summary not converted")
+	assert.Empty(suite.T(), op.Description, "Single line description is the summary")
+	assert.Equal(suite.T(), []string{"order"}, op.Tags, "Operation tags not converted")
+	assert.Equal(suite.T(), []string{parser.ContentTypeJson}, op.Consumes, "Consumed types not converted")
+	assert.Equal(suite.T(), []string{parser.ContentTypeJson}, op.Produces, "Produced types not converted")
+
+	assert.Len(suite.T(), op.Parameters, 3, "Parameters not converted")
+	assert.Equal(suite.T(), "path", op.Parameters[0].In, "Parameter location not converted")
+	assert.Equal(suite.T(), "integer", op.Parameters[0].Type, "Parameter type not converted")
+	assert.True(suite.T(), op.Parameters[0].Required, "Path parameters must be required")
+	assert.Equal(suite.T(), "formData", op.Parameters[1].In, "Form parameter location not converted")
+	assert.Equal(suite.T(), []interface{}{int64(1), int64(2)}, op.Parameters[2].Enum, "Parameter enum not converted")
+
+	assert.Len(suite.T(), op.Responses, 2, "Responses not converted")
+	assert.Equal(suite.T(), "array", op.Responses["200"].Schema.Type, "Array response not converted")
+	assert.Equal(suite.T(), "string", op.Responses["200"].Schema.Items.Type, "Array response not converted")
+	assert.Equal(suite.T(), "OK", op.Responses["200"].Description, "Missing response description not defaulted")
+	assert.Equal(suite.T(), map[string]*swagger2.Header{
+		"X-Total-Count": {Description: "Total number of orders", Type: "integer", Format: "int64"},
+	}, op.Responses["200"].Headers, "Response headers not converted")
+	assert.Equal(suite.T(), "Order ID must be specified", op.Responses["400"].Description, "Response description not converted")
+}
+
+func (suite *Swagger2Suite) TestDefinitions() {
+	op := suite.addOperation("// @Router /order [put]")
+	op.Parameters = append(op.Parameters, parser.Parameter{Name: "order", ParamType: "body", DataType: "test.Order"})
+
+	minimum := float64(1)
+	order := parser.NewModel(suite.parser)
+	order.Id = "test.Order"
+	order.Required = []string{"id"}
+	order.Properties = map[string]*parser.ModelProperty{
+		"id":    {Type: "int64", Minimum: &minimum, Enum: []string{"1", "2"}},
+		"lines": {Type: "array", Items: parser.ModelPropertyItems{Ref: "test.OrderLine"}},
+		"index": {Type: "object", AdditionalProperties: &parser.ModelProperty{Type: "test.OrderLine"}},
+	}
+	line := parser.NewModel(suite.parser)
+	line.Id = "test.OrderLine"
+	line.Properties = map[string]*parser.ModelProperty{
+		"price": {Type: "float64", Description: "Line price"},
+	}
+	op.Models = append(op.Models, order, line)
+	suite.parser.TopLevelApis["order"].AddModels(op)
+
+	spec := swagger2.NewSwagger(suite.parser)
+	assert.Len(suite.T(), spec.Definitions, 2, "Definitions not converted")
+
+	schema := spec.Definitions["test.Order"]
+	assert.Equal(suite.T(), []string{"id"}, schema.Required, "Required fields not converted")
+	assert.Equal(suite.T(), "integer", schema.Properties["id"].Type, "Property type not converted")
+	assert.Equal(suite.T(), "int64", schema.Properties["id"].Format, "Property format not converted")
+	assert.Equal(suite.T(), float64(1), *schema.Properties["id"].Minimum, "Property constraints not converted")
+	assert.Equal(suite.T(), []interface{}{int64(1), int64(2)}, schema.Properties["id"].Enum, "Property enum not converted to the property type")
+	assert.Equal(suite.T(), "#/definitions/test.OrderLine", schema.Properties["lines"].Items.Ref, "Property reference not converted")
+	assert.Equal(suite.T(), "object", schema.Properties["index"].Type, "Map property not converted")
+	assert.Equal(suite.T(), "#/definitions/test.OrderLine", schema.Properties["index"].AdditionalProperties.Ref, "Map value reference not converted")
+	assert.Equal(suite.T(), "Line price", spec.Definitions["test.OrderLine"].Properties["price"].Description, "Property description not converted")
+
+	param := spec.Paths["/order"].Put.Parameters[0]
+	assert.Equal(suite.T(), "body", param.In, "Body parameter not converted")
+	assert.Equal(suite.T(), "#/definitions/test.Order", param.Schema.Ref, "Body parameter schema not converted")
+}
+
+func (suite *Swagger2Suite) TestExamples() {
+	op := suite.addOperation(
+		"// @Param limit query int false \"Limit\" default(10) example(20)",
+		"// @Success 200 {object} string",
+		"// @Deprecated",
+		"// @Router /orders [get]",
+	)
+	op.ResponseMessages[0].Examples = map[string]string{
+		parser.ContentTypeJson:  `{"id": 1}`,
+		parser.ContentTypePlain: "order 1",
+	}
+	order := parser.NewModel(suite.parser)
+	order.Id = "test.Order"
+	order.Properties = map[string]*parser.ModelProperty{
+		"id":    {Type: "int64", Example: "1", Deprecated: true},
+		"lines": {Type: "array", Items: parser.ModelPropertyItems{Type: "int64"}, Example: "1, 2"},
+		"index": {Type: "object", AdditionalProperties: &parser.ModelProperty{Type: "string"}, Example: `{"a": "b"}`},
+	}
+	op.Models = append(op.Models, order)
+	suite.parser.TopLevelApis["orders"].AddModels(op)
+
+	spec := swagger2.NewSwagger(suite.parser)
+	properties := spec.Definitions["test.Order"].Properties
+	assert.Equal(suite.T(), int64(1), properties["id"].Example, "Example not converted to the property type")
+	assert.True(suite.T(), properties["id"].XDeprecated, "Deprecated property not converted")
+	assert.Equal(suite.T(), []interface{}{int64(1), int64(2)}, properties["lines"].Example, "Array example not converted to the item type")
+	assert.Equal(suite.T(), map[string]interface{}{"a": "b"}, properties["index"].Example, "Object example not decoded")
+
+	get := spec.Paths["/orders"].Get
+	assert.True(suite.T(), get.Deprecated, "Deprecated operation not converted")
+	assert.Equal(suite.T(), int64(10), get.Parameters[0].Default, "Param default not converted")
+	assert.Equal(suite.T(), int64(20), get.Parameters[0].Example, "Param example not converted")
+	assert.Equal(suite.T(), map[string]interface{}{
+		parser.ContentTypeJson:  map[string]interface{}{"id": float64(1)},
+		parser.ContentTypePlain: "order 1",
+	}, get.Responses["200"].Examples, "Response examples not converted")
+}
+
+func (suite *Swagger2Suite) TestTags() {
+	assert.NoError(suite.T(), suite.parser.ParseTagComment("admin Administration of the users"), "Can not parse tag comment")
+	suite.addOperation("// @Summary Delete user", "// @Tags users,admin", "// @Router /v1/users/{id} [delete]")
+	suite.addOperation("// @Summary Login", "// @Router /v1/login [post]")
+
+	assert.Len(suite.T(), suite.parser.TopLevelApis, 3, "Tagged operations not added to every resource")
+	spec := swagger2.NewSwagger(suite.parser)
+	assert.ElementsMatch(suite.T(), []*swagger2.Tag{
+		{Name: "users", Description: "Delete user"},
+		{Name: "admin", Description: "Administration of the users"},
+		{Name: "v1", Description: "Login"},
+	}, spec.Tags, "Tags not converted")
+	assert.Equal(suite.T(), []string{"users", "admin"}, spec.Paths["/v1/users/{id}"].Delete.Tags, "Operation tags not converted")
+	assert.Equal(suite.T(), []string{"v1"}, spec.Paths["/v1/login"].Post.Tags, "Untagged operations must be grouped by the first path segment")
+}
+
+func (suite *Swagger2Suite) TestSecurity() {
+	var authorization *parser.SecurityDefinition
+	for _, comment := range []string{
+		"@SecurityDefinitions.basic BasicAuth",
+		"@SecurityDefinitions.apikey ApiKeyAuth",
+		"@In query",
+		"@Name token",
+		"@SecurityDefinitions.oauth2.implicit OAuth2",
+		"@AuthorizationUrl https://example.com/oauth/authorize",
+		"@Scope.admin Grants admin access",
+	} {
+		var err error
+		authorization, err = suite.parser.ParseSecurityDefinitionComment(authorization, comment)
+		assert.NoError(suite.T(), err, "Can not parse security definition")
+	}
+	suite.addOperation("// @Security OAuth2 admin", "// @Security ApiKeyAuth", "// @Router /admin [get]")
+
+	spec := swagger2.NewSwagger(suite.parser)
+	assert.Equal(suite.T(), &swagger2.SecurityScheme{Type: "basic"}, spec.SecurityDefinitions["BasicAuth"], "Basic auth not converted")
+	assert.Equal(suite.T(), &swagger2.SecurityScheme{Type: "apiKey", Name: "token", In: "query"}, spec.SecurityDefinitions["ApiKeyAuth"], "API key not converted")
+	assert.Equal(suite.T(), &swagger2.SecurityScheme{
+		Type:             "oauth2",
+		Flow:             "implicit",
+		AuthorizationUrl: "https://example.com/oauth/authorize",
+		Scopes:           map[string]string{"admin": "Grants admin access"},
+	}, spec.SecurityDefinitions["OAuth2"], "OAuth2 not converted")
+	assert.Equal(suite.T(), []map[string][]string{{"ApiKeyAuth": {}}, {"OAuth2": {"admin"}}}, spec.Paths["/admin"].Get.Security, "Security requirements not converted")
+}
+
+func TestSwagger2Suite(t *testing.T) {
+	suite.Run(t, &Swagger2Suite{})
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"errors"
+	"go/build"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -18,8 +19,12 @@ func StringSliceContains(stringSlice []string, data string) bool {
 }
 
 // return gopath, goroot, err
+// $GOPATH is optional for module based projects, the go command default ($HOME/go) is used then
 func GetGoVars() (string, string, error) {
 	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		gopath = build.Default.GOPATH
+	}
 	if gopath == "" {
 		return "", "", errors.New("Please set the $GOPATH environment variable")
 	}